@@ -0,0 +1,56 @@
+package commonuseragent
+
+import "fmt"
+
+// GetRandomN returns n distinct agents in random order from the category
+// named by typeFilter: "desktop", "mobile", another loaded category such as
+// CategoryTV, or "" for desktop and mobile combined. If fewer than n agents
+// are available, all of them are returned. Unlike calling a GetRandom
+// function n times, the result never contains the same agent twice.
+func (m *Manager) GetRandomN(n int, typeFilter string) ([]UserAgent, error) {
+	start := m.start()
+	s := m.snap.Load()
+
+	var pool []UserAgent
+	if typeFilter == "" {
+		pool = concatAgents(s.desktop, s.mobile)
+	} else {
+		list, ok := s.category(typeFilter)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownCategory, typeFilter)
+		}
+		pool = list
+	}
+
+	// Shuffled-index sampling: a partial Fisher-Yates shuffle over the
+	// indexes of active agents stops after n swaps.
+	indexes := make([]int, 0, len(pool))
+	for i, agent := range pool {
+		if !agent.Deprecated {
+			indexes = append(indexes, i)
+		}
+	}
+	if n > len(indexes) {
+		n = len(indexes)
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+	agents := make([]UserAgent, n)
+	for i := range agents {
+		k, ok := m.rnd.intn(len(indexes) - i)
+		if !ok {
+			return nil, errRandExhausted
+		}
+		j := i + k
+		indexes[i], indexes[j] = indexes[j], indexes[i]
+		agents[i] = m.served(start, strategyUniform, typeFilter, pool[indexes[i]])
+	}
+	return agents, nil
+}
+
+// GetRandomN returns n distinct agents from the category named by
+// typeFilter. See Manager.GetRandomN.
+func GetRandomN(n int, typeFilter string) ([]UserAgent, error) {
+	return Default().GetRandomN(n, typeFilter)
+}
@@ -0,0 +1,37 @@
+package commonuseragent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetRandomN(t *testing.T) {
+	agents, err := GetRandomN(10, "desktop")
+	if err != nil {
+		t.Fatalf("GetRandomN returned an error: %v", err)
+	}
+	if len(agents) != 10 {
+		t.Fatalf("GetRandomN returned %d agents, want 10", len(agents))
+	}
+	seen := make(map[string]bool)
+	for _, agent := range agents {
+		if seen[agent.UA] {
+			t.Errorf("GetRandomN returned %q twice", agent.UA)
+		}
+		seen[agent.UA] = true
+	}
+
+	all, _ := GetRandomN(1<<20, "")
+	if want := len(GetAllDesktop()) + len(GetAllMobile()); len(all) != want {
+		t.Errorf("GetRandomN with a large n returned %d agents, want %d", len(all), want)
+	}
+	if tv, _ := GetRandomN(3, CategoryTV); len(tv) != 3 {
+		t.Errorf("GetRandomN(3, tv) returned %d agents", len(tv))
+	}
+	if none, err := GetRandomN(0, "mobile"); err != nil || len(none) != 0 {
+		t.Errorf("GetRandomN(0) = %v, %v", none, err)
+	}
+	if _, err := GetRandomN(1, "watch"); !errors.Is(err, ErrUnknownCategory) {
+		t.Errorf("GetRandomN with an unknown filter returned %v, want ErrUnknownCategory", err)
+	}
+}
@@ -0,0 +1,77 @@
+package commonuseragent
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// commentPattern matches a parenthesised comment such as "(X11; Linux x86_64)".
+	commentPattern = regexp.MustCompile(`\(([^()]*)\)`)
+	// minorVersionPattern matches the dotted tail of a version number.
+	minorVersionPattern = regexp.MustCompile(`(/\d+)[.\d]*`)
+)
+
+// CanonicalizeUA normalises a UA string without changing what it
+// identifies: surrounding whitespace is trimmed, runs of whitespace are
+// collapsed to a single space, and the "; " separators inside parenthesised
+// comments are made consistent. Product tokens are never reordered because
+// servers commonly depend on their order.
+func CanonicalizeUA(ua string) string {
+	ua = strings.Join(strings.Fields(ua), " ")
+	return commentPattern.ReplaceAllStringFunc(ua, func(comment string) string {
+		parts := strings.Split(comment[1:len(comment)-1], ";")
+		kept := parts[:0]
+		for _, part := range parts {
+			if part = strings.TrimSpace(part); part != "" {
+				kept = append(kept, part)
+			}
+		}
+		return "(" + strings.Join(kept, "; ") + ")"
+	})
+}
+
+// nearDuplicateKey reduces ua to a form in which agents that differ only in
+// case, whitespace or minor/patch version numbers compare equal.
+func nearDuplicateKey(ua string) string {
+	return strings.ToLower(minorVersionPattern.ReplaceAllString(CanonicalizeUA(ua), "$1"))
+}
+
+// IsNearDuplicate reports whether a and b identify the same browser build
+// apart from case, whitespace or minor/patch version differences.
+func IsNearDuplicate(a, b string) bool {
+	return nearDuplicateKey(a) == nearDuplicateKey(b)
+}
+
+// FindNearDuplicates groups agents that are near-duplicates of each other.
+// Only groups with at least two members are returned, in order of first
+// appearance.
+func FindNearDuplicates(agents []UserAgent) [][]UserAgent {
+	index := make(map[string]int)
+	var groups [][]UserAgent
+	for _, agent := range agents {
+		key := nearDuplicateKey(agent.UA)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], agent)
+	}
+
+	dupes := groups[:0]
+	for _, group := range groups {
+		if len(group) > 1 {
+			dupes = append(dupes, group)
+		}
+	}
+	return dupes
+}
+
+// canonicalizeAgents applies CanonicalizeUA to every agent in place.
+func canonicalizeAgents(agents []UserAgent) {
+	for i := range agents {
+		agents[i].UA = CanonicalizeUA(agents[i].UA)
+	}
+}
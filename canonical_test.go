@@ -0,0 +1,44 @@
+package commonuseragent
+
+import (
+	"testing"
+)
+
+func TestCanonicalizeUA(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"  Mozilla/5.0 (X11; Linux x86_64)  Firefox/125.0 ", "Mozilla/5.0 (X11; Linux x86_64) Firefox/125.0"},
+		{"Mozilla/5.0 (X11 ;Linux x86_64;) Firefox/125.0", "Mozilla/5.0 (X11; Linux x86_64) Firefox/125.0"},
+		{"Mozilla/5.0\t(Windows NT 10.0;  Win64; x64)", "Mozilla/5.0 (Windows NT 10.0; Win64; x64)"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalizeUA(tt.in); got != tt.want {
+			t.Errorf("CanonicalizeUA(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsNearDuplicate(t *testing.T) {
+	a := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	b := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.6367.91 Safari/537.3"
+	c := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36"
+	if !IsNearDuplicate(a, b) {
+		t.Errorf("IsNearDuplicate did not match agents differing in patch versions")
+	}
+	if IsNearDuplicate(a, c) {
+		t.Errorf("IsNearDuplicate matched agents with different major versions")
+	}
+}
+
+func TestFindNearDuplicates(t *testing.T) {
+	agents := []UserAgent{
+		{UA: "Mozilla/5.0 (X11; Linux x86_64) Firefox/125.0"},
+		{UA: "Mozilla/5.0 (X11; Linux x86_64) Firefox/124.0"},
+		{UA: "Mozilla/5.0 (X11;  Linux x86_64) Firefox/125.0.1"},
+	}
+	groups := FindNearDuplicates(agents)
+	if len(groups) != 1 || len(groups[0]) != 2 {
+		t.Errorf("FindNearDuplicates returned %+v", groups)
+	}
+}
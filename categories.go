@@ -0,0 +1,185 @@
+package commonuseragent
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+// Names of the device categories shipped alongside desktop and mobile.
+const (
+	// CategoryTV covers smart TVs and streaming sticks.
+	CategoryTV = "tv"
+	// CategoryConsole covers game consoles.
+	CategoryConsole = "console"
+)
+
+// ErrUnknownCategory is returned when a category name does not match any
+// dataset the Manager holds.
+var ErrUnknownCategory = errors.New("unknown category")
+
+// optionalCategoryFiles maps the optional categories to their dataset files.
+// Unlike desktop and mobile, a file system passed to NewManagerFromFS does
+// not need to provide them.
+var optionalCategoryFiles = map[string]string{
+	CategoryTV:      "tv_useragents.json",
+	CategoryConsole: "console_useragents.json",
+}
+
+// loadOptionalCategories loads every optional category present in fsys.
+func loadOptionalCategories(fsys fs.FS, v ValidationConfig) (map[string][]UserAgent, error) {
+	categories := make(map[string][]UserAgent)
+	for name, file := range optionalCategoryFiles {
+		agents, err := loadCategory(fsys, file, v)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		categories[name] = agents
+	}
+	return categories, nil
+}
+
+// sortedCategoryNames returns the keys of categories in a stable order.
+func sortedCategoryNames(categories map[string][]UserAgent) []string {
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// category returns the dataset called name.
+func (s *snapshot) category(name string) ([]UserAgent, bool) {
+	switch name {
+	case "desktop":
+		return s.desktop, true
+	case "mobile":
+		return s.mobile, true
+	}
+	list, ok := s.categories[name]
+	return list, ok
+}
+
+// RegisterCategory adds a category of agents, such as "smartwatch", that can
+// then be used with GetRandomFromCategory and anywhere else a category name
+// is accepted. Registering an existing category replaces it; desktop and
+// mobile cannot be replaced. The agents are copied, canonicalized and
+// validated like a loaded dataset, and registered categories survive Reload.
+func (m *Manager) RegisterCategory(name string, agents []UserAgent) error {
+	if name == "" || name == "desktop" || name == "mobile" {
+		return fmt.Errorf("cannot register category %q", name)
+	}
+	if m.readOnly {
+		return ErrReadOnly
+	}
+	agents = append([]UserAgent(nil), agents...)
+	canonicalizeAgents(agents)
+	migrateAgents(agents)
+	if err := validateDataset(name, agents, m.cfg.Validation); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.registered == nil {
+		m.registered = make(map[string][]UserAgent)
+	}
+	m.registered[name] = agents
+
+	s := m.snap.Load()
+	categories := make(map[string][]UserAgent, len(s.categories)+1)
+	for n, list := range s.categories {
+		categories[n] = list
+	}
+	categories[name] = agents
+	m.setDatasets(s.desktop, s.mobile, categories, s.warnings)
+	return nil
+}
+
+// GetRandomFromCategory returns a random agent from the named category:
+// "desktop", "mobile", a shipped category such as CategoryTV, or one added
+// with RegisterCategory. It returns ErrUnknownCategory for any other name.
+func (m *Manager) GetRandomFromCategory(name string) (UserAgent, error) {
+	start := m.start()
+	s := m.snap.Load()
+	list, ok := s.activeCategory(name)
+	if !ok {
+		return UserAgent{}, fmt.Errorf("%w: %q", ErrUnknownCategory, name)
+	}
+	return m.served(start, strategyUniform, name, s.pick(m.rnd, list)), nil
+}
+
+// GetAllTV returns a copy of all smart TV and streaming stick user agents.
+func (m *Manager) GetAllTV() []UserAgent {
+	s := m.snap.Load()
+	return append([]UserAgent(nil), s.categories[CategoryTV]...)
+}
+
+// GetAllConsole returns a copy of all game console user agents.
+func (m *Manager) GetAllConsole() []UserAgent {
+	s := m.snap.Load()
+	return append([]UserAgent(nil), s.categories[CategoryConsole]...)
+}
+
+// GetRandomTV returns a random smart TV or streaming stick user agent.
+func (m *Manager) GetRandomTV() UserAgent {
+	s := m.snap.Load()
+	return m.served(m.start(), strategyUniform, CategoryTV, s.pick(m.rnd, s.activeCategories[CategoryTV]))
+}
+
+// GetRandomConsole returns a random game console user agent.
+func (m *Manager) GetRandomConsole() UserAgent {
+	s := m.snap.Load()
+	return m.served(m.start(), strategyUniform, CategoryConsole, s.pick(m.rnd, s.activeCategories[CategoryConsole]))
+}
+
+// GetRandomTVUA returns just the UA string of a random TV user agent.
+func (m *Manager) GetRandomTVUA() string {
+	return m.GetRandomTV().UA
+}
+
+// GetRandomConsoleUA returns just the UA string of a random console user agent.
+func (m *Manager) GetRandomConsoleUA() string {
+	return m.GetRandomConsole().UA
+}
+
+// GetRandomFromCategory returns a random agent from the named category of
+// the default Manager.
+func GetRandomFromCategory(name string) (UserAgent, error) {
+	return Default().GetRandomFromCategory(name)
+}
+
+// GetAllTV returns all smart TV and streaming stick user agents.
+func GetAllTV() []UserAgent {
+	return Default().GetAllTV()
+}
+
+// GetAllConsole returns all game console user agents.
+func GetAllConsole() []UserAgent {
+	return Default().GetAllConsole()
+}
+
+// GetRandomTV returns a random smart TV or streaming stick user agent.
+func GetRandomTV() UserAgent {
+	return Default().GetRandomTV()
+}
+
+// GetRandomConsole returns a random game console user agent.
+func GetRandomConsole() UserAgent {
+	return Default().GetRandomConsole()
+}
+
+// GetRandomTVUA returns just the UA string of a random TV user agent.
+func GetRandomTVUA() string {
+	return GetRandomTV().UA
+}
+
+// GetRandomConsoleUA returns just the UA string of a random console user agent.
+func GetRandomConsoleUA() string {
+	return GetRandomConsole().UA
+}
@@ -0,0 +1,81 @@
+package commonuseragent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetRandomTV(t *testing.T) {
+	if len(GetAllTV()) == 0 {
+		t.Errorf("GetAllTV returned an empty slice")
+	}
+	if GetRandomTVUA() == "" {
+		t.Errorf("GetRandomTVUA returned an empty user agent")
+	}
+}
+
+func TestGetRandomConsole(t *testing.T) {
+	if len(GetAllConsole()) == 0 {
+		t.Errorf("GetAllConsole returned an empty slice")
+	}
+	if GetRandomConsoleUA() == "" {
+		t.Errorf("GetRandomConsoleUA returned an empty user agent")
+	}
+}
+
+func TestRegisterCategory(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	watch := "Mozilla/5.0 (Linux; Android 11; SM-R890) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/2.0 Chrome/87.0.4280.141 Mobile Safari/537.36"
+	if err := m.RegisterCategory("smartwatch", []UserAgent{{UA: watch, Pct: 1}}); err != nil {
+		t.Fatalf("RegisterCategory returned an error: %v", err)
+	}
+
+	agent, err := m.GetRandomFromCategory("smartwatch")
+	if err != nil || agent.UA != watch {
+		t.Errorf("GetRandomFromCategory(smartwatch) = %+v, %v", agent, err)
+	}
+	if agent.Engine != EngineBlink || agent.Browser != "Samsung Internet" {
+		t.Errorf("registered agent was not tagged: %+v", agent)
+	}
+	if agents, _ := m.GetRandomN(5, "smartwatch"); len(agents) != 1 {
+		t.Errorf("GetRandomN(smartwatch) returned %d agents, want 1", len(agents))
+	}
+	if _, err := m.GetRandomFromCategory("desktop"); err != nil {
+		t.Errorf("GetRandomFromCategory(desktop) returned an error: %v", err)
+	}
+	if _, err := m.GetRandomFromCategory("fridge"); !errors.Is(err, ErrUnknownCategory) {
+		t.Errorf("GetRandomFromCategory(fridge) error = %v, want ErrUnknownCategory", err)
+	}
+
+	if err := m.RegisterCategory("mobile", nil); err == nil {
+		t.Errorf("RegisterCategory replaced the mobile dataset")
+	}
+	if err := m.RegisterCategory("bad", []UserAgent{{UA: "x"}}); err == nil {
+		t.Errorf("RegisterCategory accepted an invalid agent")
+	}
+}
+
+func TestRegisterCategoryOverlappingDesktop(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	desktop := m.GetAllDesktop()
+	if err := m.RegisterCategory("x", desktop[:3]); err != nil {
+		t.Fatalf("RegisterCategory returned an error: %v", err)
+	}
+
+	agent, err := m.GetRandomFromCategory("x")
+	if err != nil || agent.UA == "" {
+		t.Errorf("GetRandomFromCategory(x) = %+v, %v", agent, err)
+	}
+	if got := m.Stats().Agents["x"]; got != 3 {
+		t.Errorf("category x holds %d agents, want 3", got)
+	}
+	if got := m.GetAllDesktop()[0].Pct; got != desktop[0].Pct {
+		t.Errorf("desktop Pct changed from %v to %v after registering an overlapping category", desktop[0].Pct, got)
+	}
+}
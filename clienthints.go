@@ -0,0 +1,119 @@
+package commonuseragent
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrNoClientHints is returned by ClientHints for user agents whose browser
+// does not send User-Agent Client Hints: anything not built on Chromium,
+// Chromium builds older than 89, and every browser on iOS.
+var ErrNoClientHints = errors.New("browser does not send client hints")
+
+// minClientHintsVersion is the first Chromium release that sent the
+// Sec-CH-UA headers by default.
+const minClientHintsVersion = 89
+
+// Hints holds the low-entropy User-Agent Client Hints header values a
+// browser sends alongside its User-Agent header.
+type Hints struct {
+	SecCHUA         string
+	SecCHUAMobile   string
+	SecCHUAPlatform string
+}
+
+// Apply sets the hint headers on h.
+func (hints Hints) Apply(h http.Header) {
+	h.Set("Sec-CH-UA", hints.SecCHUA)
+	h.Set("Sec-CH-UA-Mobile", hints.SecCHUAMobile)
+	h.Set("Sec-CH-UA-Platform", hints.SecCHUAPlatform)
+}
+
+var chromiumVersionPattern = regexp.MustCompile(`Chrome/(\d+)`)
+
+// chromiumBrands maps Parse browser names to the brand they report in
+// Sec-CH-UA. Browsers missing here only report Chromium.
+var chromiumBrands = map[string]string{
+	"Chrome":           "Google Chrome",
+	"Edge":             "Microsoft Edge",
+	"Opera":            "Opera",
+	"Samsung Internet": "Samsung Internet",
+}
+
+// chPlatforms maps Parse OS names to Sec-CH-UA-Platform values.
+var chPlatforms = map[string]string{
+	"Windows":  "Windows",
+	"macOS":    "macOS",
+	"Linux":    "Linux",
+	"Android":  "Android",
+	"ChromeOS": "Chrome OS",
+}
+
+// ClientHints returns the Sec-CH-UA, Sec-CH-UA-Mobile and Sec-CH-UA-Platform
+// values consistent with ua, so requests don't advertise one browser in
+// User-Agent and another in their client hints. It returns ErrNoClientHints
+// for browsers that do not send them.
+func ClientHints(ua string) (Hints, error) {
+	p, err := Parse(ua)
+	if err != nil {
+		return Hints{}, err
+	}
+	m := chromiumVersionPattern.FindStringSubmatch(ua)
+	if m == nil || p.OS == "iOS" || p.Engine != EngineBlink {
+		return Hints{}, ErrNoClientHints
+	}
+	chromium, _ := strconv.Atoi(m[1])
+	if chromium < minClientHintsVersion {
+		return Hints{}, ErrNoClientHints
+	}
+
+	brandVersion := chromium
+	if v, err := strconv.Atoi(strings.SplitN(p.BrowserVersion, ".", 2)[0]); err == nil {
+		brandVersion = v
+	}
+
+	platform, ok := chPlatforms[p.OS]
+	if !ok {
+		platform = "Unknown"
+	}
+	mobile := "?0"
+	if p.Device == DeviceMobile {
+		mobile = "?1"
+	}
+
+	return Hints{
+		SecCHUA:         brandList(chromium, chromiumBrands[p.Browser], brandVersion),
+		SecCHUAMobile:   mobile,
+		SecCHUAPlatform: strconv.Quote(platform),
+	}, nil
+}
+
+// brandList builds a Sec-CH-UA value the way Chromium does: a GREASE brand,
+// Chromium itself and the browser's own brand, in an order and with GREASE
+// characters derived from the Chromium major version.
+func brandList(chromium int, brand string, brandVersion int) string {
+	greaseChars := []string{" ", "(", ":", "-", ".", "/", ")", ";", "=", "?", "_"}
+	greaseVersions := []string{"8", "99", "24"}
+	orders := [][3]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+
+	grease := fmt.Sprintf(`"Not%sA%sBrand";v="%s"`,
+		greaseChars[chromium%len(greaseChars)],
+		greaseChars[(chromium+1)%len(greaseChars)],
+		greaseVersions[chromium%len(greaseVersions)])
+	entries := []string{grease, fmt.Sprintf(`"Chromium";v="%d"`, chromium)}
+	if brand == "" {
+		return strings.Join(entries, ", ")
+	}
+	entries = append(entries, fmt.Sprintf(`"%s";v="%d"`, brand, brandVersion))
+
+	order := orders[chromium%len(orders)]
+	list := make([]string, 3)
+	for i, pos := range order {
+		list[pos] = entries[i]
+	}
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,57 @@
+package commonuseragent
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestClientHints(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want Hints
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			Hints{`"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`, "?0", `"Windows"`},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36",
+			Hints{`"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`, "?1", `"Android"`},
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36 OPR/109.0.0.0",
+			Hints{`"Opera";v="109", "Not:A-Brand";v="8", "Chromium";v="123"`, "?0", `"Windows"`},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ClientHints(tt.ua)
+		if err != nil {
+			t.Errorf("ClientHints(%q) returned an error: %v", tt.ua, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ClientHints(%q) = %+v, want %+v", tt.ua, got, tt.want)
+		}
+	}
+}
+
+func TestClientHintsUnsupported(t *testing.T) {
+	for _, ua := range []string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/124.0.6367.88 Mobile/15E148 Safari/604.1",
+		"Mozilla/5.0 (Windows NT 6.1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/69.0.3497.100 Safari/537.36",
+	} {
+		if _, err := ClientHints(ua); !errors.Is(err, ErrNoClientHints) {
+			t.Errorf("ClientHints(%q) error = %v, want ErrNoClientHints", ua, err)
+		}
+	}
+}
+
+func TestHintsApply(t *testing.T) {
+	h := http.Header{}
+	Hints{SecCHUA: "a", SecCHUAMobile: "?0", SecCHUAPlatform: `"Linux"`}.Apply(h)
+	if h.Get("Sec-CH-UA-Platform") != `"Linux"` || h.Get("Sec-CH-UA") != "a" {
+		t.Errorf("Apply set headers %v", h)
+	}
+}
@@ -0,0 +1,66 @@
+package commonuseragent
+
+import (
+	"errors"
+)
+
+// ErrReadOnly is returned when changing the data of a Manager returned by
+// Snapshot.
+var ErrReadOnly = errors.New("manager is a read-only snapshot")
+
+// Clone returns an independent Manager holding the same data and settings.
+// Later changes to either Manager, including Reload, Deprecate and
+// AddDesktop, do not affect the other. Cloning is cheap: the datasets are
+// shared until one side changes them. The clone draws from its own random
+// source; a clone of a Manager built WithSeed continues the original's
+// sequence from the point it was cloned without consuming it. A clone of a
+// Snapshot can be changed again.
+func (m *Manager) Clone() *Manager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := &Manager{
+		cfg:      m.cfg,
+		rnd:      cloneSource(m.rnd),
+		fsys:     m.fsys,
+		stamp:    m.stamp,
+		exclude:  m.exclude,
+		replayMu: m.replayMu,
+	}
+	c.snap.Store(m.snap.Load())
+	c.info.Store(m.info.Load())
+	if m.registered != nil {
+		c.registered = make(map[string][]UserAgent, len(m.registered))
+		for name, agents := range m.registered {
+			c.registered[name] = agents
+		}
+	}
+	m.sessionMu.Lock()
+	if m.sessions != nil {
+		c.sessions = make(map[string]sessionState, len(m.sessions))
+		for id, st := range m.sessions {
+			c.sessions[id] = st
+		}
+	}
+	c.sessionSweep = m.sessionSweep
+	m.sessionMu.Unlock()
+	if m.deprecated != nil {
+		c.deprecated = make(map[string]bool, len(m.deprecated))
+		for ua, d := range m.deprecated {
+			c.deprecated[ua] = d
+		}
+	}
+	return c
+}
+
+// Snapshot returns a read-only Manager frozen at the data m holds now, so a
+// batch job can work from one consistent dataset version while m keeps
+// reloading. Reload and Watch on it return ErrReloadUnsupported, AddDesktop,
+// AddMobile and RegisterCategory return ErrReadOnly, and Remove, Deprecate
+// and Undeprecate report false without changing anything.
+func (m *Manager) Snapshot() *Manager {
+	c := m.Clone()
+	c.fsys = nil
+	c.readOnly = true
+	return c
+}
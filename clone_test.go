@@ -0,0 +1,88 @@
+package commonuseragent
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCloneIsIndependent(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	c := m.Clone()
+	ua := m.GetAllDesktop()[0].UA
+
+	c.Remove(ua)
+	if len(m.GetAllDesktop()) == len(c.GetAllDesktop()) {
+		t.Errorf("Remove on a clone changed the original")
+	}
+	m.Deprecate(m.GetAllMobile()[0].UA)
+	if c.GetAllMobile()[0].Deprecated {
+		t.Errorf("Deprecate on the original changed the clone")
+	}
+}
+
+func TestSnapshotSurvivesReload(t *testing.T) {
+	fsys := fstest.MapFS{
+		"desktop_useragents.json": &fstest.MapFile{Data: []byte(`[{"ua": "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", "pct": 100}]`)},
+		"mobile_useragents.json":  &fstest.MapFile{Data: []byte(`[{"ua": "Mozilla/5.0 (Android 14; Mobile; rv:125.0) Gecko/125.0 Firefox/125.0", "pct": 100}]`)},
+	}
+	m, err := NewManagerFromFS(fsys, DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromFS returned an error: %v", err)
+	}
+	snap := m.Snapshot()
+
+	fsys["desktop_useragents.json"] = &fstest.MapFile{Data: []byte(`[{"ua": "Mozilla/5.0 (X11; Linux x86_64; rv:126.0) Gecko/20100101 Firefox/126.0", "pct": 100}]`)}
+	if err := m.Reload(); err != nil {
+		t.Fatalf("Reload returned an error: %v", err)
+	}
+	if got := snap.GetRandomDesktopUA(); got != "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0" {
+		t.Errorf("snapshot returned %q after the original reloaded", got)
+	}
+	if err := snap.Reload(); !errors.Is(err, ErrReloadUnsupported) {
+		t.Errorf("Reload on a snapshot returned %v, want ErrReloadUnsupported", err)
+	}
+}
+
+func TestSnapshotIsReadOnly(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	snap := m.Snapshot()
+	ua := snap.GetAllDesktop()[0].UA
+
+	if err := snap.AddDesktop(UserAgent{UA: "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", Pct: 1}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("AddDesktop on a snapshot returned %v, want ErrReadOnly", err)
+	}
+	if err := snap.RegisterCategory("x", snap.GetAllMobile()); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("RegisterCategory on a snapshot returned %v, want ErrReadOnly", err)
+	}
+	if snap.Remove(ua) || snap.Deprecate(ua) {
+		t.Errorf("Remove or Deprecate reported success on a snapshot")
+	}
+	if all := snap.GetAllDesktop(); all[0].UA != ua || all[0].Deprecated {
+		t.Errorf("snapshot data changed: %+v", all[0])
+	}
+	if !snap.Clone().Remove(ua) {
+		t.Errorf("a clone of a snapshot could not be edited")
+	}
+}
+
+func TestCloneHasOwnSeededSource(t *testing.T) {
+	m, err := NewManager(WithSeed(7))
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	m.GetRandomUA()
+	c := m.Clone()
+
+	for i := 0; i < 50; i++ {
+		if got, want := c.GetRandomUA(), m.GetRandomUA(); got != want {
+			t.Fatalf("selection %d: clone returned %q, original %q", i, got, want)
+		}
+	}
+}
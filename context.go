@@ -0,0 +1,60 @@
+package commonuseragent
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetRandomUAContext is like GetRandomUA but returns ctx.Err() if ctx is
+// already done, and ErrNoMatch instead of an empty string when there are no
+// agents. Selection reads an immutable snapshot and never waits on a reload
+// or remote refresh, so today only an already cancelled context is
+// reported; the context variants give callers one place to thread
+// cancellation through should a future source need to block.
+func (m *Manager) GetRandomUAContext(ctx context.Context) (string, error) {
+	agent, err := selectContext(ctx, "desktop or mobile", func() UserAgent {
+		return UserAgent{UA: m.GetRandomUA()}
+	})
+	return agent.UA, err
+}
+
+// GetRandomDesktopContext is like GetRandomDesktop but respects ctx. See
+// GetRandomUAContext.
+func (m *Manager) GetRandomDesktopContext(ctx context.Context) (UserAgent, error) {
+	return selectContext(ctx, "desktop", m.GetRandomDesktop)
+}
+
+// GetRandomMobileContext is like GetRandomMobile but respects ctx. See
+// GetRandomUAContext.
+func (m *Manager) GetRandomMobileContext(ctx context.Context) (UserAgent, error) {
+	return selectContext(ctx, "mobile", m.GetRandomMobile)
+}
+
+// selectContext runs pick unless ctx is done, turning an empty result into
+// ErrNoMatch.
+func selectContext(ctx context.Context, what string, pick func() UserAgent) (UserAgent, error) {
+	if err := ctx.Err(); err != nil {
+		return UserAgent{}, err
+	}
+	agent := pick()
+	if agent.UA == "" {
+		return UserAgent{}, fmt.Errorf("%s: %w", what, ErrNoMatch)
+	}
+	return agent, nil
+}
+
+// GetRandomUAContext returns a random desktop or mobile UA string unless ctx
+// is done. See Manager.GetRandomUAContext.
+func GetRandomUAContext(ctx context.Context) (string, error) {
+	return Default().GetRandomUAContext(ctx)
+}
+
+// GetRandomDesktopContext returns a random desktop agent unless ctx is done.
+func GetRandomDesktopContext(ctx context.Context) (UserAgent, error) {
+	return Default().GetRandomDesktopContext(ctx)
+}
+
+// GetRandomMobileContext returns a random mobile agent unless ctx is done.
+func GetRandomMobileContext(ctx context.Context) (UserAgent, error) {
+	return Default().GetRandomMobileContext(ctx)
+}
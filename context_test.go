@@ -0,0 +1,29 @@
+package commonuseragent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetRandomUAContext(t *testing.T) {
+	ctx := context.Background()
+	if ua, err := GetRandomUAContext(ctx); err != nil || ua == "" {
+		t.Errorf("GetRandomUAContext = %q, %v", ua, err)
+	}
+	if agent, err := GetRandomDesktopContext(ctx); err != nil || agent.UA == "" {
+		t.Errorf("GetRandomDesktopContext = %+v, %v", agent, err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := GetRandomMobileContext(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetRandomMobileContext with a cancelled context returned %v", err)
+	}
+
+	empty, _ := NewManagerFromReaders(strings.NewReader(`[]`), strings.NewReader(`[]`), DefaultConfig())
+	if _, err := empty.GetRandomUAContext(ctx); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("GetRandomUAContext on an empty Manager returned %v, want ErrNoMatch", err)
+	}
+}
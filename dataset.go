@@ -0,0 +1,130 @@
+package commonuseragent
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Names of the embedded dataset files.
+const (
+	desktopFile = "desktop_useragents.json"
+	mobileFile  = "mobile_useragents.json"
+)
+
+// openDataset opens the dataset file called name in fsys, or a gzip
+// compressed copy of it with a .gz suffix if the plain file does not exist.
+func openDataset(fsys fs.FS, name string) (io.ReadCloser, error) {
+	f, err := fsys.Open(name)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return f, err
+	}
+	gz, gzErr := fsys.Open(name + ".gz")
+	if gzErr != nil {
+		// Report the plain name, which is what callers asked for.
+		return nil, err
+	}
+	zr, err := gzip.NewReader(gz)
+	if err != nil {
+		gz.Close()
+		return nil, fmt.Errorf("%s.gz: %w", name, err)
+	}
+	return gzipFile{zr, gz}, nil
+}
+
+// gzipFile closes both the decompressor and the underlying file.
+type gzipFile struct {
+	*gzip.Reader
+	file fs.File
+}
+
+func (f gzipFile) Close() error {
+	f.Reader.Close()
+	return f.file.Close()
+}
+
+// datasetCandidates returns the file names a dataset called name may be
+// stored under, in the order they are looked for.
+func datasetCandidates(name string) []string {
+	jsonl := strings.TrimSuffix(name, path.Ext(name)) + ".jsonl"
+	return []string{name, name + ".gz", jsonl, jsonl + ".gz"}
+}
+
+// loadDataset reads a dataset file from fsys. Files with a .jsonl extension
+// are decoded record by record; anything else goes through DecodeDataset.
+// Either kind may be stored gzip-compressed with an added .gz suffix. Every
+// UA string is passed through CanonicalizeUA and migrated to the current
+// schema.
+func loadDataset(fsys fs.FS, name string) ([]UserAgent, error) {
+	f, err := openDataset(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if path.Ext(name) == ".jsonl" {
+		agents, err := LoadJSONL(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		canonicalizeAgents(agents)
+		migrateAgents(agents)
+		return agents, nil
+	}
+
+	agents, err := DecodeDataset(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	canonicalizeAgents(agents)
+	return agents, nil
+}
+
+// loadCategory loads and validates the dataset called name from fsys,
+// falling back to a .jsonl file with the same base name if the JSON file
+// does not exist.
+func loadCategory(fsys fs.FS, name string, v ValidationConfig) ([]UserAgent, error) {
+	agents, err := loadDataset(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		jsonl := strings.TrimSuffix(name, path.Ext(name)) + ".jsonl"
+		if agents, err = loadDataset(fsys, jsonl); err == nil {
+			name = jsonl
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDataset(name, agents, v); err != nil {
+		return nil, err
+	}
+	return agents, nil
+}
+
+// readCategory decodes and validates a dataset read from r; name is only
+// used in error messages.
+func readCategory(name string, r io.Reader, v ValidationConfig) ([]UserAgent, error) {
+	agents, err := DecodeDataset(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	canonicalizeAgents(agents)
+	if err := validateDataset(name, agents, v); err != nil {
+		return nil, err
+	}
+	return agents, nil
+}
+
+// validateDataset runs every agent through v, reporting the first failure
+// together with the file it came from and its position.
+func validateDataset(name string, agents []UserAgent, v ValidationConfig) error {
+	for i, agent := range agents {
+		if err := v.Validate(agent); err != nil {
+			return fmt.Errorf("%s: entry %d: %w", name, i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package commonuseragent
+
+// Deprecate marks every agent with the given UA string as deprecated. The
+// agent stays in GetAll results with Deprecated set but is no longer returned
+// by random selection. The mark is kept across Reload, Watch and
+// RemoteSource refreshes until Undeprecate is called. It reports whether any
+// agent was found.
+func (m *Manager) Deprecate(ua string) bool {
+	return m.setDeprecated(ua, true)
+}
+
+// Undeprecate reverses Deprecate, making the agent eligible for random
+// selection again, even if a reloaded dataset marks it deprecated. It
+// reports whether any agent was found.
+func (m *Manager) Undeprecate(ua string) bool {
+	return m.setDeprecated(ua, false)
+}
+
+func (m *Manager) setDeprecated(ua string, deprecated bool) bool {
+	if m.readOnly {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Published snapshots are immutable, so mark the agents on copies.
+	s := m.snap.Load()
+	found := false
+	mark := func(list []UserAgent) []UserAgent {
+		list = append([]UserAgent(nil), list...)
+		for i := range list {
+			if list[i].UA == ua {
+				list[i].Deprecated = deprecated
+				found = true
+			}
+		}
+		return list
+	}
+	categories := make(map[string][]UserAgent, len(s.categories))
+	for name, list := range s.categories {
+		categories[name] = mark(list)
+	}
+	m.snap.Store(newSnapshot(mark(s.desktop), mark(s.mobile), categories, s.warnings, m.cfg.Freshness))
+	if found {
+		if m.deprecated == nil {
+			m.deprecated = make(map[string]bool)
+		}
+		m.deprecated[ua] = deprecated
+	}
+	return found
+}
+
+// applyDeprecated sets the Deprecated flag of agents from the runtime
+// overrides in deprecated.
+func applyDeprecated(deprecated map[string]bool, agents []UserAgent) {
+	if len(deprecated) == 0 {
+		return
+	}
+	for i := range agents {
+		if d, ok := deprecated[agents[i].UA]; ok {
+			agents[i].Deprecated = d
+		}
+	}
+}
+
+// countDeprecated returns how many agents across lists are deprecated.
+func countDeprecated(lists ...[]UserAgent) int {
+	n := 0
+	for _, list := range lists {
+		for _, agent := range list {
+			if agent.Deprecated {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,79 @@
+package commonuseragent
+
+import (
+	"testing"
+)
+
+func TestDeprecate(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	desktop := m.GetAllDesktop()
+	for _, agent := range desktop[1:] {
+		if !m.Deprecate(agent.UA) {
+			t.Fatalf("Deprecate did not find %q", agent.UA)
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		if got := m.GetRandomDesktop(); got.UA != desktop[0].UA {
+			t.Fatalf("GetRandomDesktop returned deprecated agent %q", got.UA)
+		}
+	}
+	all := m.GetAllDesktop()
+	if len(all) != len(desktop) || !all[1].Deprecated {
+		t.Errorf("GetAllDesktop did not return deprecated agents with the flag set")
+	}
+
+	if m.Deprecate("not in the dataset") {
+		t.Errorf("Deprecate reported an unknown agent as found")
+	}
+	m.Undeprecate(desktop[1].UA)
+	if m.GetAllDesktop()[1].Deprecated {
+		t.Errorf("Undeprecate did not clear the flag")
+	}
+}
+
+func TestDeprecateSurvivesReload(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	desktop := m.GetAllDesktop()
+	m.Deprecate(desktop[0].UA)
+	m.Deprecate(desktop[1].UA)
+	m.Undeprecate(desktop[1].UA)
+
+	if err := m.Reload(); err != nil {
+		t.Fatalf("Reload returned an error: %v", err)
+	}
+	all := m.GetAllDesktop()
+	if !all[0].Deprecated {
+		t.Errorf("Reload cleared a runtime deprecation")
+	}
+	if all[1].Deprecated {
+		t.Errorf("Reload restored an agent that was undeprecated")
+	}
+	c := m.Clone()
+	if err := c.Reload(); err != nil || !c.GetAllDesktop()[0].Deprecated {
+		t.Errorf("Clone did not keep the runtime deprecation across Reload: %v", err)
+	}
+}
+
+func TestPickWithDeprecatedDoesNotAllocate(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	m.Deprecate(m.GetAllMobile()[0].UA)
+	if allocs := testing.AllocsPerRun(100, func() { m.GetRandomUA() }); allocs != 0 {
+		t.Errorf("GetRandomUA made %v allocations per call with a deprecated agent, want 0", allocs)
+	}
+	deprecated := m.GetAllMobile()[0].UA
+	for i := 0; i < 200; i++ {
+		if m.GetRandomMobileUA() == deprecated {
+			t.Fatalf("GetRandomMobile returned deprecated agent %q", deprecated)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package commonuseragent
+
+import (
+	"fmt"
+	"slices"
+)
+
+// DuplicatePolicy decides what happens to a UA string that appears more than
+// once across the datasets a Manager loads.
+type DuplicatePolicy int
+
+const (
+	// DuplicatesMerge keeps the first occurrence within a dataset and adds
+	// the Pct of every later occurrence in the same dataset to it, so the
+	// agent is not double-counted. A UA found in two datasets, such as
+	// desktop and mobile, is kept in both, since each dataset's Pct values
+	// are shares of a different population; it is only reported through
+	// Manager.Warnings.
+	DuplicatesMerge DuplicatePolicy = iota
+	// DuplicatesKeep leaves the datasets untouched and only reports the
+	// duplicates through Manager.Warnings.
+	DuplicatesKeep
+)
+
+// WithDuplicatePolicy sets how duplicate UA strings are handled at load time.
+func WithDuplicatePolicy(p DuplicatePolicy) Option {
+	return func(c *Config) {
+		c.Duplicates = p
+	}
+}
+
+// LoadWarning describes a problem found while loading datasets that did not
+// prevent the Manager from being built.
+type LoadWarning struct {
+	// UA is the user agent the warning is about.
+	UA string
+	// Sources lists the dataset of every occurrence, in load order.
+	Sources []string
+	// Merged reports whether the occurrences were combined into one entry.
+	Merged bool
+}
+
+func (w LoadWarning) String() string {
+	action := "kept"
+	if w.Merged {
+		action = "merged"
+	}
+	return fmt.Sprintf("duplicate user agent %q in %v (%s)", w.UA, w.Sources, action)
+}
+
+// dataset is a named list of agents taking part in duplicate detection.
+type dataset struct {
+	name   string
+	agents *[]UserAgent
+}
+
+// dedupe finds UA strings that occur more than once across sets, applies
+// policy within each set and returns one warning per duplicated UA.
+// Occurrences in different sets are reported but never merged.
+func dedupe(policy DuplicatePolicy, sets ...dataset) []LoadWarning {
+	first := make(map[string]int)
+	warnings := make(map[string]*LoadWarning)
+	var order []string
+	warn := func(ua, source string) *LoadWarning {
+		w, ok := warnings[ua]
+		if !ok {
+			w = &LoadWarning{UA: ua, Sources: []string{sets[first[ua]].name}}
+			warnings[ua] = w
+			order = append(order, ua)
+		}
+		w.Sources = append(w.Sources, source)
+		return w
+	}
+
+	for s, set := range sets {
+		kept := (*set.agents)[:0:0]
+		// index maps each UA to its first position in kept.
+		index := make(map[string]int)
+		for _, agent := range *set.agents {
+			if i, ok := index[agent.UA]; ok {
+				w := warn(agent.UA, set.name)
+				if policy == DuplicatesMerge {
+					w.Merged = true
+					kept[i].Pct += agent.Pct
+					continue
+				}
+			} else {
+				index[agent.UA] = len(kept)
+				if _, seen := first[agent.UA]; seen {
+					warn(agent.UA, set.name)
+				} else {
+					first[agent.UA] = s
+				}
+			}
+			kept = append(kept, agent)
+		}
+		*set.agents = kept
+	}
+
+	result := make([]LoadWarning, 0, len(order))
+	for _, ua := range order {
+		result = append(result, *warnings[ua])
+	}
+	return result
+}
+
+// mergeWarnings returns prior followed by the warnings in found about user
+// agents prior does not already cover.
+func mergeWarnings(prior, found []LoadWarning) []LoadWarning {
+	if len(prior) == 0 {
+		return found
+	}
+	merged := append([]LoadWarning(nil), prior...)
+	for _, w := range found {
+		if !slices.ContainsFunc(prior, func(p LoadWarning) bool { return p.UA == w.UA }) {
+			merged = append(merged, w)
+		}
+	}
+	return merged
+}
+
+// Warnings returns the problems found while loading the Manager's datasets,
+// such as duplicated user agents.
+func (m *Manager) Warnings() []LoadWarning {
+	s := m.snap.Load()
+	return append([]LoadWarning(nil), s.warnings...)
+}
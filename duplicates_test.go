@@ -0,0 +1,84 @@
+package commonuseragent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDedupeMerge(t *testing.T) {
+	desktop := []UserAgent{{UA: "a", Pct: 1}, {UA: "b", Pct: 2}, {UA: "a", Pct: 3}}
+	mobile := []UserAgent{{UA: "b", Pct: 4}, {UA: "c", Pct: 5}}
+
+	warnings := dedupe(DuplicatesMerge,
+		dataset{name: "desktop", agents: &desktop},
+		dataset{name: "mobile", agents: &mobile},
+	)
+
+	if len(desktop) != 2 || desktop[0].Pct != 4 || desktop[1].Pct != 2 {
+		t.Errorf("dedupe left desktop as %+v", desktop)
+	}
+	if len(mobile) != 2 || mobile[0].UA != "b" || mobile[0].Pct != 4 {
+		t.Errorf("dedupe merged across datasets, leaving mobile as %+v", mobile)
+	}
+	if len(warnings) != 2 || !warnings[0].Merged || warnings[1].Merged {
+		t.Fatalf("dedupe returned warnings %+v", warnings)
+	}
+	if got := warnings[1].Sources; len(got) != 2 || got[0] != "desktop" || got[1] != "mobile" {
+		t.Errorf("dedupe reported sources %v for %q", got, warnings[1].UA)
+	}
+}
+
+func TestDedupeKeep(t *testing.T) {
+	desktop := []UserAgent{{UA: "a", Pct: 1}, {UA: "a", Pct: 3}}
+
+	warnings := dedupe(DuplicatesKeep, dataset{name: "desktop", agents: &desktop})
+
+	if len(desktop) != 2 {
+		t.Errorf("dedupe removed entries under DuplicatesKeep: %+v", desktop)
+	}
+	if len(warnings) != 1 || warnings[0].Merged {
+		t.Errorf("dedupe returned warnings %+v", warnings)
+	}
+}
+
+func TestManagerWarnings(t *testing.T) {
+	if w := Default().Warnings(); len(w) != 0 {
+		t.Errorf("embedded datasets produced warnings: %v", w)
+	}
+}
+
+func TestWarningsSurviveEdits(t *testing.T) {
+	const firefox = "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0"
+	const chrome = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	desktop := strings.NewReader(`[{"ua": "` + firefox + `", "pct": 1}, {"ua": "` + firefox + `", "pct": 2}, {"ua": "` + chrome + `", "pct": 3}]`)
+	m, err := NewManagerFromReaders(desktop, strings.NewReader(`[]`), DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromReaders returned an error: %v", err)
+	}
+	if len(m.Warnings()) != 1 {
+		t.Fatalf("Warnings() = %v, want 1 warning", m.Warnings())
+	}
+
+	edits := []struct {
+		name string
+		edit func()
+	}{
+		{"AddMobile", func() {
+			m.AddMobile(UserAgent{UA: "Mozilla/5.0 (Android 14; Mobile; rv:125.0) Gecko/125.0 Firefox/125.0", Pct: 1})
+		}},
+		{"Remove", func() { m.Remove(chrome) }},
+		{"RegisterCategory", func() { m.RegisterCategory("x", m.GetAllDesktop()) }},
+		{"Deprecate", func() { m.Deprecate(firefox) }},
+	}
+	for _, e := range edits {
+		e.edit()
+		if w := m.Warnings(); len(w) != 1 || w[0].UA != firefox {
+			t.Errorf("after %s, Warnings() = %v", e.name, w)
+		}
+	}
+	m.AddDesktop(UserAgent{UA: chrome, Pct: 1})
+	m.AddDesktop(UserAgent{UA: chrome, Pct: 1})
+	if w := m.Warnings(); len(w) != 2 {
+		t.Errorf("adding a duplicate did not add a warning: %v", w)
+	}
+}
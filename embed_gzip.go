@@ -0,0 +1,16 @@
+//go:build !commonuseragent_nogzip
+
+package commonuseragent
+
+import "embed"
+
+// content holds gzip-compressed copies of the datasets, generated from the
+// JSON files by go generate, to keep binaries small. Build with the
+// commonuseragent_nogzip tag to embed the plain JSON instead.
+//
+//go:embed desktop_useragents.json.gz
+//go:embed mobile_useragents.json.gz
+//go:embed tv_useragents.json.gz
+//go:embed console_useragents.json.gz
+//go:embed manifest.json
+var content embed.FS
@@ -0,0 +1,15 @@
+//go:build commonuseragent_nogzip
+
+package commonuseragent
+
+import "embed"
+
+// content holds the datasets as plain JSON, skipping decompression at load
+// at the cost of a larger binary.
+//
+//go:embed desktop_useragents.json
+//go:embed mobile_useragents.json
+//go:embed tv_useragents.json
+//go:embed console_useragents.json
+//go:embed manifest.json
+var content embed.FS
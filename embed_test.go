@@ -0,0 +1,53 @@
+package commonuseragent
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCompressedDatasetsUpToDate(t *testing.T) {
+	for _, name := range []string{desktopFile, mobileFile, "tv_useragents.json", "console_useragents.json"} {
+		plain, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(name + ".gz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("%s.gz: %v", name, err)
+		}
+		unzipped, err := io.ReadAll(zr)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s.gz: %v", name, err)
+		}
+		if !bytes.Equal(plain, unzipped) {
+			t.Errorf("%s.gz is stale; run go generate", name)
+		}
+	}
+}
+
+func TestLoadGzipDataset(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(`[{"ua": "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", "pct": 100}]`))
+	zw.Close()
+	fsys := fstest.MapFS{
+		"desktop_useragents.json.gz": &fstest.MapFile{Data: buf.Bytes()},
+		"mobile_useragents.json":     &fstest.MapFile{Data: []byte(`[{"ua": "Mozilla/5.0 (Android 14; Mobile; rv:125.0) Gecko/125.0 Firefox/125.0", "pct": 100}]`)},
+	}
+	m, err := NewManagerFromFS(fsys, DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromFS returned an error: %v", err)
+	}
+	if got := m.GetRandomDesktopUA(); got != "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0" {
+		t.Errorf("GetRandomDesktopUA returned %q", got)
+	}
+}
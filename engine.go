@@ -0,0 +1,86 @@
+package commonuseragent
+
+import (
+	"strings"
+)
+
+// Engine identifies the rendering engine of a browser.
+type Engine string
+
+// Known rendering engines. EngineUnknown is used when a UA does not match any.
+const (
+	EngineUnknown Engine = ""
+	EngineBlink   Engine = "blink"
+	EngineGecko   Engine = "gecko"
+	EngineWebKit  Engine = "webkit"
+	EngineTrident Engine = "trident"
+	// EngineEdgeHTML is the engine of legacy (pre-Chromium) Edge, still
+	// found on Xbox, whose UA also carries a Chrome/ token.
+	EngineEdgeHTML Engine = "edgehtml"
+)
+
+// DetectEngine returns the rendering engine advertised by a UA string.
+func DetectEngine(ua string) Engine {
+	switch {
+	// Every browser on iOS is required to use WebKit, whatever it calls itself.
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"), strings.Contains(ua, "iPod"):
+		return EngineWebKit
+	case strings.Contains(ua, "Trident/"):
+		return EngineTrident
+	case strings.Contains(ua, "Firefox/"), strings.Contains(ua, "Gecko/"):
+		return EngineGecko
+	// Legacy Edge says Edge/; Chromium-based Edge says Edg/.
+	case strings.Contains(ua, "Edge/"):
+		return EngineEdgeHTML
+	case strings.Contains(ua, "Chrome/"), strings.Contains(ua, "Chromium/"):
+		return EngineBlink
+	case strings.Contains(ua, "AppleWebKit/"):
+		return EngineWebKit
+	}
+	return EngineUnknown
+}
+
+// tagEngines fills in the Engine of every agent that does not already have one.
+func tagEngines(agents []UserAgent) {
+	for i := range agents {
+		if agents[i].Engine == EngineUnknown {
+			agents[i].Engine = DetectEngine(agents[i].UA)
+		}
+	}
+}
+
+// GetAllByEngine returns all desktop and mobile agents using engine.
+func (m *Manager) GetAllByEngine(engine Engine) []UserAgent {
+	s := m.snap.Load()
+	return filterByEngine(engine, s.desktop, s.mobile)
+}
+
+// GetRandomByEngine returns a random desktop or mobile agent using engine,
+// or the zero UserAgent if there is none.
+func (m *Manager) GetRandomByEngine(engine Engine) UserAgent {
+	s := m.snap.Load()
+	return m.served(m.start(), strategyEngine, "", s.pick(m.rnd, filterByEngine(engine, s.activeDesktop, s.activeMobile)))
+}
+
+// filterByEngine returns the agents of lists using engine.
+func filterByEngine(engine Engine, lists ...[]UserAgent) []UserAgent {
+	var matched []UserAgent
+	for _, list := range lists {
+		for _, agent := range list {
+			if agent.Engine == engine {
+				matched = append(matched, agent)
+			}
+		}
+	}
+	return matched
+}
+
+// GetAllByEngine returns all agents using the given rendering engine.
+func GetAllByEngine(engine Engine) []UserAgent {
+	return Default().GetAllByEngine(engine)
+}
+
+// GetRandomByEngine returns a random agent using the given rendering engine.
+func GetRandomByEngine(engine Engine) UserAgent {
+	return Default().GetRandomByEngine(engine)
+}
@@ -0,0 +1,38 @@
+package commonuseragent
+
+import (
+	"testing"
+)
+
+func TestDetectEngine(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want Engine
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", EngineBlink},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0", EngineGecko},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15", EngineWebKit},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/124.0.6367.88 Mobile/15E148 Safari/604.1", EngineWebKit},
+		{"Mozilla/5.0 (Windows NT 6.1; WOW64; Trident/7.0; rv:11.0) like Gecko", EngineTrident},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; XBOX_ONE_ED) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.102 Safari/537.36 Edge/18.19041", EngineEdgeHTML},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0", EngineBlink},
+		{"curl/8.5.0", EngineUnknown},
+	}
+	for _, tt := range tests {
+		if got := DetectEngine(tt.ua); got != tt.want {
+			t.Errorf("DetectEngine(%q) = %q, want %q", tt.ua, got, tt.want)
+		}
+	}
+}
+
+func TestGetRandomByEngine(t *testing.T) {
+	for _, engine := range []Engine{EngineBlink, EngineGecko, EngineWebKit} {
+		agent := GetRandomByEngine(engine)
+		if agent.UA == "" || agent.Engine != engine {
+			t.Errorf("GetRandomByEngine(%q) returned %+v", engine, agent)
+		}
+		if len(GetAllByEngine(engine)) == 0 {
+			t.Errorf("GetAllByEngine(%q) returned an empty slice", engine)
+		}
+	}
+}
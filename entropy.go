@@ -0,0 +1,170 @@
+package commonuseragent
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	mathrand "math/rand/v2"
+	"sync"
+)
+
+// errRandExhausted is returned by selections that report errors when the
+// Manager's random source has run dry.
+var errRandExhausted = errors.New("random source exhausted")
+
+// entropyChunkSize is how many bytes the pool reads from crypto/rand at a time.
+const entropyChunkSize = 4096
+
+// randSource produces the random indices used for selection. Both methods
+// report false if the source has run dry, in which case selections return
+// the zero UserAgent.
+type randSource interface {
+	// intn returns a uniformly distributed value in [0, n). n must be > 0.
+	intn(n int) (int, bool)
+	// float64 returns a uniformly distributed value in [0, 1).
+	float64() (float64, bool)
+}
+
+// entropyPool hands out random numbers from a buffer filled from a
+// cryptographically secure reader in large chunks, so a selection costs a
+// few bytes of buffer rather than a syscall and a big.Int allocation.
+type entropyPool struct {
+	mu     sync.Mutex
+	reader io.Reader
+	buf    []byte
+	off    int
+}
+
+// newEntropyPool returns a pool reading chunk bytes at a time from r. Only
+// endless sources such as crypto/rand.Reader should be read in large chunks;
+// a chunk of 8 consumes a finite reader exactly as far as selections need.
+func newEntropyPool(r io.Reader, chunk int) *entropyPool {
+	return &entropyPool{
+		reader: r,
+		buf:    make([]byte, chunk),
+		off:    chunk,
+	}
+}
+
+// newReaderPool returns the pool for a reader passed to WithRandSource.
+func newReaderPool(r io.Reader) *entropyPool {
+	if _, ok := r.(*seededReader); ok {
+		return newEntropyPool(r, entropyChunkSize)
+	}
+	return newEntropyPool(r, 8)
+}
+
+// defaultPool is shared by every Manager that has no source of its own.
+var defaultPool = newEntropyPool(rand.Reader, entropyChunkSize)
+
+// cloneSource returns a source for a cloned Manager that does not share
+// state with src. A seeded source is copied so the clone continues the same
+// deterministic sequence independently. A reader supplied through
+// WithRandSource cannot be copied, so that source stays shared.
+func cloneSource(src randSource) randSource {
+	p, ok := src.(*entropyPool)
+	if !ok || p == defaultPool {
+		return src
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	seeded, ok := p.reader.(*seededReader)
+	if !ok {
+		return src
+	}
+	pcg := *seeded.src
+	return &entropyPool{
+		reader: &seededReader{src: &pcg},
+		buf:    append([]byte(nil), p.buf...),
+		off:    p.off,
+	}
+}
+
+// uint64 returns 8 random bytes from the buffer, refilling it when
+// exhausted. It reports false if the reader cannot fill the buffer.
+func (p *entropyPool) uint64() (uint64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.off+8 > len(p.buf) {
+		if _, err := io.ReadFull(p.reader, p.buf); err != nil {
+			return 0, false
+		}
+		p.off = 0
+	}
+	v := binary.LittleEndian.Uint64(p.buf[p.off:])
+	p.off += 8
+	return v, true
+}
+
+func (p *entropyPool) intn(n int) (int, bool) {
+	bound := uint64(n)
+	// Reject the top values that would make the modulo biased towards
+	// small results; threshold is 2^64 mod n, so [0, 2^64-threshold) holds
+	// a whole number of ranges. Low values such as an all-zero input are
+	// always accepted.
+	threshold := -bound % bound
+	for {
+		v, ok := p.uint64()
+		if !ok {
+			return 0, false
+		}
+		if threshold == 0 || v < -threshold {
+			return int(v % bound), true
+		}
+	}
+}
+
+func (p *entropyPool) float64() (float64, bool) {
+	v, ok := p.uint64()
+	// Use the top 53 bits so every value is exactly representable.
+	return float64(v>>11) / (1 << 53), ok
+}
+
+// WithRandSource makes the Manager draw its randomness from src instead of
+// crypto/rand. src is read 8 bytes at a time, only as selections need them,
+// so a fixed byte slice gives a reproducible sequence. Once src returns an
+// error or runs dry, selections return the zero UserAgent.
+func WithRandSource(src io.Reader) Option {
+	return func(c *Config) {
+		c.Rand = src
+	}
+}
+
+// WithSeed makes selections deterministic: two Managers built with the same
+// seed and data return the same sequence of agents. It is meant for tests and
+// reproducible experiments; the output is predictable, so production code
+// should keep the crypto/rand default.
+func WithSeed(seed int64) Option {
+	return WithRandSource(&seededReader{src: mathrand.NewPCG(uint64(seed), 0)})
+}
+
+// WithFastRand swaps crypto/rand for math/rand/v2's runtime generator, which
+// keeps per-CPU state and needs no lock, for load generators making millions
+// of selections. It takes precedence over WithSeed and WithRandSource. The
+// output is fast but predictable to an attacker who sees enough of it.
+func WithFastRand() Option {
+	return func(c *Config) {
+		c.FastRand = true
+	}
+}
+
+// fastSource draws from math/rand/v2's global generator.
+type fastSource struct{}
+
+func (fastSource) intn(n int) (int, bool)   { return mathrand.IntN(n), true }
+func (fastSource) float64() (float64, bool) { return mathrand.Float64(), true }
+
+// seededReader turns a deterministic generator into an endless io.Reader.
+type seededReader struct {
+	src *mathrand.PCG
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	var b [8]byte
+	for i := 0; i < len(p); i += 8 {
+		binary.LittleEndian.PutUint64(b[:], r.src.Uint64())
+		copy(p[i:], b[:])
+	}
+	return len(p), nil
+}
@@ -0,0 +1,128 @@
+package commonuseragent
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+// countingReader counts how many times the pool goes back to its source.
+type countingReader struct {
+	reads int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads++
+	return rand.Read(p)
+}
+
+func TestEntropyPoolIntnRange(t *testing.T) {
+	pool := newEntropyPool(rand.Reader, entropyChunkSize)
+	for _, n := range []int{1, 2, 7, 21, 1000} {
+		for i := 0; i < 1000; i++ {
+			if v, ok := pool.intn(n); !ok || v < 0 || v >= n {
+				t.Fatalf("intn(%d) returned %d", n, v)
+			}
+		}
+	}
+}
+
+func TestEntropyPoolBuffersReads(t *testing.T) {
+	src := &countingReader{}
+	pool := newEntropyPool(src, entropyChunkSize)
+	for i := 0; i < entropyChunkSize/8; i++ {
+		pool.intn(16)
+	}
+	if src.reads != 1 {
+		t.Errorf("pool read from its source %d times, want 1", src.reads)
+	}
+}
+
+func BenchmarkGetRandomUA(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		GetRandomUA()
+	}
+}
+
+// The source benchmarks compare crypto/rand with WithFastRand on a selection
+// that does nothing but pick, run in parallel to show lock contention.
+func BenchmarkSourceCrypto(b *testing.B) {
+	benchmarkSource(b, Default())
+}
+
+func BenchmarkSourceFast(b *testing.B) {
+	m, err := NewManager(WithFastRand())
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkSource(b, m)
+}
+
+func benchmarkSource(b *testing.B, m *Manager) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.GetRandomDesktopUA()
+		}
+	})
+}
+
+func TestWithRandSourceFiniteReader(t *testing.T) {
+	m, err := NewManager(WithRandSource(bytes.NewReader(make([]byte, 64))))
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	// 64 bytes are enough for 8 uniform picks; after that the source is dry.
+	for i := 0; i < 8; i++ {
+		if m.GetRandomUA() == "" {
+			t.Fatalf("pick %d returned an empty user agent from a reader with bytes left", i)
+		}
+	}
+	if ua := m.GetRandomUA(); ua != "" {
+		t.Errorf("GetRandomUA returned %q from a dry source, want \"\"", ua)
+	}
+	if _, err := m.GetRandomN(2, ""); err == nil {
+		t.Errorf("GetRandomN did not report the dry source")
+	}
+}
+
+func TestWithFastRand(t *testing.T) {
+	m, err := NewManager(WithFastRand(), WithSeed(1))
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	if _, ok := m.rnd.(fastSource); !ok {
+		t.Errorf("WithFastRand did not take precedence over WithSeed")
+	}
+	if m.GetRandomUA() == "" {
+		t.Errorf("GetRandomUA returned an empty user agent in fast mode")
+	}
+}
+
+func TestWithSeed(t *testing.T) {
+	a, err := NewManager(WithSeed(42))
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	b, _ := NewManager(WithSeed(42))
+	c, _ := NewManager(WithSeed(43))
+
+	same, differs := true, false
+	for i := 0; i < 50; i++ {
+		ua := a.GetRandomUA()
+		same = same && ua == b.GetRandomUA()
+		differs = differs || ua != c.GetRandomUA()
+	}
+	if !same {
+		t.Errorf("Managers with the same seed produced different sequences")
+	}
+	if !differs {
+		t.Errorf("Managers with different seeds produced the same sequence")
+	}
+}
+
+func TestGetRandomUADoesNotAllocate(t *testing.T) {
+	if allocs := testing.AllocsPerRun(100, func() { GetRandomUA() }); allocs != 0 {
+		t.Errorf("GetRandomUA made %v allocations per call, want 0", allocs)
+	}
+}
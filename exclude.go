@@ -0,0 +1,73 @@
+package commonuseragent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// WithExcludePatterns bans agents whose UA string matches any of patterns
+// from ever being returned, for example an outdated browser version or an
+// operating system a target site blocks. A pattern wrapped in slashes, such
+// as `/Chrome\/1[01]\d\./`, is a regular expression; anything else is a
+// plain substring. Exclusions are applied once each time datasets are
+// loaded, so they cost nothing at selection time.
+func WithExcludePatterns(patterns []string) Option {
+	return func(c *Config) {
+		c.ExcludePatterns = append(c.ExcludePatterns, patterns...)
+	}
+}
+
+// excludeFilter holds compiled exclude patterns.
+type excludeFilter struct {
+	substrings []string
+	regexps    []*regexp.Regexp
+}
+
+// compileExcludePatterns parses patterns as described by WithExcludePatterns.
+func compileExcludePatterns(patterns []string) (excludeFilter, error) {
+	var f excludeFilter
+	for _, p := range patterns {
+		if len(p) >= 2 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/") {
+			re, err := regexp.Compile(p[1 : len(p)-1])
+			if err != nil {
+				return excludeFilter{}, fmt.Errorf("exclude pattern %q: %w", p, err)
+			}
+			f.regexps = append(f.regexps, re)
+			continue
+		}
+		if p != "" {
+			f.substrings = append(f.substrings, p)
+		}
+	}
+	return f, nil
+}
+
+// matches reports whether ua is excluded.
+func (f excludeFilter) matches(ua string) bool {
+	for _, s := range f.substrings {
+		if strings.Contains(ua, s) {
+			return true
+		}
+	}
+	for _, re := range f.regexps {
+		if re.MatchString(ua) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply returns agents without the excluded ones. agents is not modified.
+func (f excludeFilter) apply(agents []UserAgent) []UserAgent {
+	if len(f.substrings) == 0 && len(f.regexps) == 0 {
+		return agents
+	}
+	kept := make([]UserAgent, 0, len(agents))
+	for _, agent := range agents {
+		if !f.matches(agent.UA) {
+			kept = append(kept, agent)
+		}
+	}
+	return kept
+}
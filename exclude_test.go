@@ -0,0 +1,31 @@
+package commonuseragent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithExcludePatterns(t *testing.T) {
+	m, err := NewManager(WithExcludePatterns([]string{"Firefox/", `/Windows NT (6|10)\.\d/`}))
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	all := append(m.GetAllDesktop(), m.GetAllMobile()...)
+	if len(all) == 0 {
+		t.Fatalf("exclusions removed every agent")
+	}
+	for _, agent := range all {
+		if strings.Contains(agent.UA, "Firefox/") || strings.Contains(agent.UA, "Windows NT") {
+			t.Errorf("excluded agent %q was kept", agent.UA)
+		}
+	}
+	if len(all) >= len(GetAllDesktop())+len(GetAllMobile()) {
+		t.Errorf("exclusions removed nothing")
+	}
+}
+
+func TestWithExcludePatternsInvalidRegexp(t *testing.T) {
+	if _, err := NewManager(WithExcludePatterns([]string{"/Chrome/(/"})); err == nil {
+		t.Errorf("NewManager accepted an invalid exclude regexp")
+	}
+}
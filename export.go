@@ -0,0 +1,117 @@
+package commonuseragent
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Format is a serialisation format accepted by Export.
+type Format string
+
+// Formats supported by Export.
+const (
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+	FormatYAML   Format = "yaml"
+)
+
+// exportRecord is one exported agent together with the dataset it is in.
+type exportRecord struct {
+	Category string `json:"category"`
+	UserAgent
+}
+
+// csvHeader lists the CSV columns in the order Export writes them.
+var csvHeader = []string{"category", "ua", "pct", "browser", "browser_version", "os", "device", "engine", "deprecated"}
+
+// Export writes every agent the Manager holds to w in format, each tagged
+// with its category, so spreadsheets and data pipelines can consume the
+// lists directly. FormatJSON writes a single array, FormatNDJSON one object
+// per line, FormatCSV a header row followed by one row per agent, and
+// FormatYAML a sequence of mappings.
+func (m *Manager) Export(w io.Writer, format Format) error {
+	s := m.snap.Load()
+	var records []exportRecord
+	for _, name := range append([]string{"desktop", "mobile"}, sortedCategoryNames(s.categories)...) {
+		list, _ := s.category(name)
+		for _, agent := range list {
+			records = append(records, exportRecord{Category: name, UserAgent: agent})
+		}
+	}
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatCSV:
+		return exportCSV(w, records)
+	case FormatYAML:
+		return exportYAML(w, records)
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+func exportCSV(w io.Writer, records []exportRecord) error {
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	for _, r := range records {
+		cw.Write([]string{
+			r.Category,
+			r.UA,
+			strconv.FormatFloat(r.Pct, 'f', -1, 64),
+			r.Browser,
+			r.BrowserVersion,
+			r.OS,
+			string(r.Device),
+			string(r.Engine),
+			strconv.FormatBool(r.Deprecated),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportYAML writes records as a YAML sequence. Strings are emitted as JSON
+// strings, which are valid YAML double-quoted scalars, so no YAML library is
+// needed. Empty optional fields are left out as in JSON.
+func exportYAML(w io.Writer, records []exportRecord) error {
+	bw := bufio.NewWriter(w)
+	str := func(s string) string {
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+	for _, r := range records {
+		fmt.Fprintf(bw, "- category: %s\n", str(r.Category))
+		fmt.Fprintf(bw, "  ua: %s\n", str(r.UA))
+		fmt.Fprintf(bw, "  pct: %s\n", strconv.FormatFloat(r.Pct, 'f', -1, 64))
+		for _, f := range []struct{ key, value string }{
+			{"browser", r.Browser},
+			{"browser_version", r.BrowserVersion},
+			{"os", r.OS},
+			{"device", string(r.Device)},
+			{"engine", string(r.Engine)},
+		} {
+			if f.value != "" {
+				fmt.Fprintf(bw, "  %s: %s\n", f.key, str(f.value))
+			}
+		}
+		if r.Deprecated {
+			fmt.Fprintf(bw, "  deprecated: true\n")
+		}
+	}
+	return bw.Flush()
+}
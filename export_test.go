@@ -0,0 +1,85 @@
+package commonuseragent
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func newExportManager(t *testing.T) *Manager {
+	t.Helper()
+	desktop := strings.NewReader(`[{"ua": "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", "pct": 60.5}]`)
+	mobile := strings.NewReader(`[{"ua": "Mozilla/5.0 (Android 14; Mobile; rv:125.0) Gecko/125.0 Firefox/125.0", "pct": 39.5, "deprecated": true}]`)
+	m, err := NewManagerFromReaders(desktop, mobile, DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromReaders returned an error: %v", err)
+	}
+	return m
+}
+
+func TestExportJSONAndNDJSON(t *testing.T) {
+	m := newExportManager(t)
+
+	var buf bytes.Buffer
+	if err := m.Export(&buf, FormatJSON); err != nil {
+		t.Fatalf("Export(json) returned an error: %v", err)
+	}
+	var records []exportRecord
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("Export(json) wrote invalid JSON: %v", err)
+	}
+	if len(records) != 2 || records[0].Category != "desktop" || records[1].Category != "mobile" || !records[1].Deprecated {
+		t.Errorf("Export(json) = %+v", records)
+	}
+
+	buf.Reset()
+	if err := m.Export(&buf, FormatNDJSON); err != nil {
+		t.Fatalf("Export(ndjson) returned an error: %v", err)
+	}
+	agents, err := LoadJSONL(&buf)
+	if err != nil || len(agents) != 2 || agents[0].Pct != 60.5 {
+		t.Errorf("Export(ndjson) did not round-trip through LoadJSONL: %+v, %v", agents, err)
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newExportManager(t).Export(&buf, FormatCSV); err != nil {
+		t.Fatalf("Export(csv) returned an error: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Export(csv) wrote invalid CSV: %v", err)
+	}
+	if len(rows) != 3 || strings.Join(rows[0], ",") != strings.Join(csvHeader, ",") {
+		t.Fatalf("Export(csv) rows = %q", rows)
+	}
+	want := []string{"desktop", "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", "60.5", "Firefox", "125.0", "Linux", "desktop", "gecko", "false"}
+	if strings.Join(rows[1], "|") != strings.Join(want, "|") {
+		t.Errorf("Export(csv) row = %q, want %q", rows[1], want)
+	}
+}
+
+func TestExportYAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newExportManager(t).Export(&buf, FormatYAML); err != nil {
+		t.Fatalf("Export(yaml) returned an error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"- category: \"desktop\"\n  ua: \"Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0\"\n  pct: 60.5\n",
+		"  deprecated: true\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Export(yaml) output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	if err := newExportManager(t).Export(&bytes.Buffer{}, Format("xml")); err == nil {
+		t.Errorf("Export accepted an unknown format")
+	}
+}
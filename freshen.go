@@ -0,0 +1,123 @@
+package commonuseragent
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// releaseCadence is roughly how often each browser family ships a new major
+// version. Families without an entry are not projected past the dataset.
+var releaseCadence = map[string]time.Duration{
+	"chrome":      4 * 7 * 24 * time.Hour,
+	"chrome-ios":  4 * 7 * 24 * time.Hour,
+	"edge":        4 * 7 * 24 * time.Hour,
+	"opera":       4 * 7 * 24 * time.Hour,
+	"firefox":     4 * 7 * 24 * time.Hour,
+	"firefox-ios": 4 * 7 * 24 * time.Hour,
+	"safari":      365 * 24 * time.Hour,
+}
+
+// companionTokens lists the other version tokens that must move together
+// with a family's own version token to keep a rewritten UA consistent.
+var companionTokens = map[string][]string{
+	// Gecko repeats the version in the rv: token.
+	"firefox": {"rv:"},
+	// Edge and Opera ship on a Chromium release that moves in step with
+	// their own version.
+	"edge":  {"Chrome/"},
+	"opera": {"Chrome/"},
+	// Safari on iOS and iPadOS shares its major version with the OS.
+	"safari": {"iPhone OS ", "CPU OS "},
+}
+
+// FreshenUA rewrites the browser version in ua to a recent one, so a UA
+// template from an older dataset keeps looking current between releases.
+// The current major version of each browser is projected from the newest
+// version in the Manager's datasets, the date the data was collected (see
+// DatasetInfo) and the browser's usual release cadence; without a collection
+// date the newest version in the datasets is used. The new version is drawn
+// at random from the projected one and the MaxReleasesBehind releases before
+// it. Versions are never lowered, and UAs whose browser version cannot be
+// read are returned unchanged.
+func (m *Manager) FreshenUA(ua string) string {
+	return m.freshenUA(ua, time.Now())
+}
+
+// freshenUA is FreshenUA with the current time passed in.
+func (m *Manager) freshenUA(ua string, now time.Time) string {
+	family, _, ok := browserMajorVersion(ua)
+	if !ok {
+		return ua
+	}
+	target, ok := m.projectedMajor(family, now)
+	if !ok {
+		return ua
+	}
+	if lag := m.cfg.Freshness.MaxReleasesBehind; lag > 0 {
+		behind, _ := m.rnd.intn(lag + 1)
+		target -= behind
+	}
+	return FreshenUAToVersion(ua, target)
+}
+
+// projectedMajor estimates the current major version of family at now.
+func (m *Manager) projectedMajor(family string, now time.Time) (int, bool) {
+	latest, ok := m.snap.Load().latest[family]
+	if !ok {
+		return 0, false
+	}
+	collected := m.DatasetInfo().Collected
+	if cadence, ok := releaseCadence[family]; ok && !collected.IsZero() && now.After(collected) {
+		latest += int(now.Sub(collected) / cadence)
+	}
+	return latest, true
+}
+
+// FreshenUAToVersion rewrites the browser major version in ua to major,
+// along with the tokens that must match it: Firefox's rv:, the Chromium
+// version of Edge and Opera, and the OS version of Safari on iOS. UAs that
+// already report major or newer, or whose browser version cannot be read,
+// are returned unchanged.
+func FreshenUAToVersion(ua string, major int) string {
+	family, current, ok := browserMajorVersion(ua)
+	if !ok || major <= current {
+		return ua
+	}
+	delta := major - current
+	for _, vt := range versionTokens {
+		if vt.family == family {
+			ua = shiftMajor(ua, vt.token, delta)
+			break
+		}
+	}
+	for _, token := range companionTokens[family] {
+		ua = shiftMajor(ua, token, delta)
+	}
+	return ua
+}
+
+// shiftMajor adds delta to the major version that follows the first
+// occurrence of token in ua.
+func shiftMajor(ua, token string, delta int) string {
+	i := strings.Index(ua, token)
+	if i < 0 {
+		return ua
+	}
+	start := i + len(token)
+	end := start
+	for end < len(ua) && ua[end] >= '0' && ua[end] <= '9' {
+		end++
+	}
+	major, err := strconv.Atoi(ua[start:end])
+	if err != nil {
+		return ua
+	}
+	return ua[:start] + strconv.Itoa(major+delta) + ua[end:]
+}
+
+// FreshenUA rewrites the browser version in ua to a recent one. See
+// Manager.FreshenUA.
+func FreshenUA(ua string) string {
+	return Default().FreshenUA(ua)
+}
@@ -0,0 +1,107 @@
+package commonuseragent
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestFreshenUA(t *testing.T) {
+	m, err := NewManager(WithFreshness(FreshnessConfig{MaxReleasesBehind: 0, Decay: 0.5}))
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	latest := m.snap.Load().latest
+	collected := m.DatasetInfo().Collected
+
+	old := "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:90.0) Gecko/20100101 Firefox/90.0"
+	fresh := m.freshenUA(old, collected)
+	_, major, _ := browserMajorVersion(fresh)
+	if major != latest["firefox"] {
+		t.Errorf("FreshenUA(%q) = %q, want Firefox %d", old, fresh, latest["firefox"])
+	}
+	if want := "rv:" + strings.Split(strings.SplitN(fresh, "Firefox/", 2)[1], ".")[0] + "."; !strings.Contains(fresh, want) {
+		t.Errorf("FreshenUA(%q) = %q, rv: token does not match", old, fresh)
+	}
+
+	edge := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/100.0.4896.75 Safari/537.36 Edg/100.0.1185.39"
+	if latest["edge"] > 100 {
+		fresh := m.freshenUA(edge, collected)
+		if _, major, _ := browserMajorVersion(fresh); major != latest["edge"] || !strings.Contains(fresh, "Chrome/"+strings.Split(strings.SplitN(fresh, "Edg/", 2)[1], ".")[0]+".") {
+			t.Errorf("FreshenUA(%q) = %q", edge, fresh)
+		}
+	}
+
+	for _, ua := range []string{"curl/8.0.1", "Mozilla/5.0 (X11; Linux x86_64; rv:999.0) Gecko/20100101 Firefox/999.0"} {
+		if got := m.freshenUA(ua, collected); got != ua {
+			t.Errorf("FreshenUA(%q) = %q, want it unchanged", ua, got)
+		}
+	}
+}
+
+func TestFreshenUAProjectsPastDataset(t *testing.T) {
+	fsys := fstest.MapFS{
+		"desktop_useragents.json": &fstest.MapFile{Data: []byte(`[
+			{"ua": "Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0", "pct": 50},
+			{"ua": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 OPR/106.0.0.0", "pct": 50}
+		]`)},
+		"mobile_useragents.json": &fstest.MapFile{Data: []byte(`[{"ua": "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1", "pct": 100}]`)},
+		"manifest.json":          &fstest.MapFile{Data: []byte(`{"version": "2024.01", "collected": "2024-01-01"}`)},
+	}
+	cfg := DefaultConfig()
+	cfg.Freshness.MaxReleasesBehind = 0
+	m, err := NewManagerFromFS(fsys, cfg)
+	if err != nil {
+		t.Fatalf("NewManagerFromFS returned an error: %v", err)
+	}
+	// Thirteen four-week releases and one year after collection.
+	now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		ua   string
+		want string
+	}{
+		{
+			"Mozilla/5.0 (X11; Linux x86_64; rv:115.0) Gecko/20100101 Firefox/115.0",
+			"Mozilla/5.0 (X11; Linux x86_64; rv:133.0) Gecko/20100101 Firefox/133.0",
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/118.0.0.0 Safari/537.36 OPR/104.0.0.0",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/133.0.0.0 Safari/537.36 OPR/119.0.0.0",
+		},
+		{
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 18_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.6 Mobile/15E148 Safari/604.1",
+		},
+	}
+	for _, tt := range tests {
+		if got := m.freshenUA(tt.ua, now); got != tt.want {
+			t.Errorf("FreshenUA(%q)\n got %q\nwant %q", tt.ua, got, tt.want)
+		}
+	}
+}
+
+func TestFreshenUAToVersion(t *testing.T) {
+	ua := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91"
+	want := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36 Edg/131.0.2210.91"
+	if got := FreshenUAToVersion(ua, 131); got != want {
+		t.Errorf("FreshenUAToVersion(%q, 131) = %q, want %q", ua, got, want)
+	}
+	if got := FreshenUAToVersion(ua, 110); got != ua {
+		t.Errorf("FreshenUAToVersion lowered the version: %q", got)
+	}
+}
+
+func TestFreshenUAWithinLag(t *testing.T) {
+	m := Default()
+	latest, _ := m.projectedMajor("chrome", time.Now())
+	lag := DefaultFreshnessConfig().MaxReleasesBehind
+	for i := 0; i < 20; i++ {
+		fresh := FreshenUA("Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.0.0 Safari/537.36")
+		_, major, _ := browserMajorVersion(fresh)
+		if major < latest-lag || major > latest {
+			t.Errorf("FreshenUA produced Chrome %d, want %d-%d", major, latest-lag, latest)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package commonuseragent
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FreshnessConfig controls how strongly outdated browser versions are
+// down-weighted by the GetFresh* selectors.
+type FreshnessConfig struct {
+	// MaxReleasesBehind is how many major releases an agent may trail the
+	// newest version of its browser in the dataset before it is penalised.
+	MaxReleasesBehind int
+	// Decay multiplies the score once for every release beyond
+	// MaxReleasesBehind. It should be in (0, 1]; 1 disables decay.
+	Decay float64
+}
+
+// DefaultFreshnessConfig tolerates two releases of lag and halves the score
+// for every release after that.
+func DefaultFreshnessConfig() FreshnessConfig {
+	return FreshnessConfig{
+		MaxReleasesBehind: 2,
+		Decay:             0.5,
+	}
+}
+
+// WithFreshness sets the freshness scoring used by the GetFresh* selectors.
+func WithFreshness(f FreshnessConfig) Option {
+	return func(c *Config) {
+		c.Freshness = f
+	}
+}
+
+// versionTokens are checked in order, so more specific browsers that also
+// carry a Chrome/ or Safari token must come first.
+var versionTokens = []struct {
+	token  string
+	family string
+}{
+	{"Edg/", "edge"},
+	{"OPR/", "opera"},
+	{"SamsungBrowser/", "samsung"},
+	{"HuaweiBrowser/", "huawei"},
+	{"CriOS/", "chrome-ios"},
+	{"FxiOS/", "firefox-ios"},
+	{"Firefox/", "firefox"},
+	{"Chrome/", "chrome"},
+	{"Version/", "safari"},
+}
+
+// browserMajorVersion returns the browser family and major version of ua.
+func browserMajorVersion(ua string) (family string, major int, ok bool) {
+	for _, vt := range versionTokens {
+		i := strings.Index(ua, vt.token)
+		if i < 0 {
+			continue
+		}
+		rest := ua[i+len(vt.token):]
+		end := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+		if end < 0 {
+			end = len(rest)
+		}
+		n, err := strconv.Atoi(rest[:end])
+		if err != nil {
+			return "", 0, false
+		}
+		return vt.family, n, true
+	}
+	return "", 0, false
+}
+
+// latestVersions returns the newest major version seen for every browser family.
+func latestVersions(lists ...[]UserAgent) map[string]int {
+	latest := make(map[string]int)
+	for _, list := range lists {
+		for _, agent := range list {
+			family, major, ok := browserMajorVersion(agent.UA)
+			if ok && major > latest[family] {
+				latest[family] = major
+			}
+		}
+	}
+	return latest
+}
+
+// score returns a value in (0, 1] describing how current agent is compared
+// with latest. Agents whose browser or version cannot be determined score 1.
+func (f FreshnessConfig) score(agent UserAgent, latest map[string]int) float64 {
+	family, major, ok := browserMajorVersion(agent.UA)
+	if !ok {
+		return 1
+	}
+	behind := latest[family] - major - f.MaxReleasesBehind
+	if behind <= 0 || f.Decay <= 0 || f.Decay >= 1 {
+		return 1
+	}
+	return math.Pow(f.Decay, float64(behind))
+}
+
+// FreshnessScore returns how current agent's browser version is relative to
+// the newest version of the same browser in the Manager's datasets, from 1
+// (current) towards 0 (many releases behind).
+func (m *Manager) FreshnessScore(agent UserAgent) float64 {
+	s := m.snap.Load()
+	return m.cfg.Freshness.score(agent, s.latest)
+}
+
+// GetFreshRandomDesktop returns a desktop agent chosen with probability
+// proportional to its Pct multiplied by its freshness score.
+func (m *Manager) GetFreshRandomDesktop() UserAgent {
+	s := m.snap.Load()
+	return m.served(m.start(), strategyFresh, "desktop", s.pickByWeight(m.rnd, s.freshDesktopWeights, s.activeDesktop))
+}
+
+// GetFreshRandomMobile returns a mobile agent chosen with probability
+// proportional to its Pct multiplied by its freshness score.
+func (m *Manager) GetFreshRandomMobile() UserAgent {
+	s := m.snap.Load()
+	return m.served(m.start(), strategyFresh, "mobile", s.pickByWeight(m.rnd, s.freshMobileWeights, s.activeMobile))
+}
+
+// newFreshWeights is like newCumulativeWeights but multiplies each agent's
+// Pct by its freshness score, so each UA is parsed once per snapshot rather
+// than on every GetFresh* call.
+func newFreshWeights(f FreshnessConfig, latest map[string]int, agents []UserAgent) cumulativeWeights {
+	cum := make(cumulativeWeights, 0, len(agents))
+	total := 0.0
+	for _, agent := range agents {
+		if !agent.Deprecated && agent.Pct > 0 {
+			total += agent.Pct * f.score(agent, latest)
+		}
+		cum = append(cum, total)
+	}
+	return cum
+}
+
+// GetFreshRandomDesktop returns a desktop agent weighted by Pct and freshness.
+func GetFreshRandomDesktop() UserAgent {
+	return Default().GetFreshRandomDesktop()
+}
+
+// GetFreshRandomMobile returns a mobile agent weighted by Pct and freshness.
+func GetFreshRandomMobile() UserAgent {
+	return Default().GetFreshRandomMobile()
+}
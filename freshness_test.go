@@ -0,0 +1,71 @@
+package commonuseragent
+
+import (
+	"testing"
+)
+
+func TestBrowserMajorVersion(t *testing.T) {
+	tests := []struct {
+		ua     string
+		family string
+		major  int
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0", "edge", 124},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0", "firefox", 125},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.1 Mobile/15E148 Safari/604.1", "safari", 16},
+	}
+	for _, tt := range tests {
+		family, major, ok := browserMajorVersion(tt.ua)
+		if !ok || family != tt.family || major != tt.major {
+			t.Errorf("browserMajorVersion(%q) = %q, %d, %v", tt.ua, family, major, ok)
+		}
+	}
+}
+
+func TestFreshnessScore(t *testing.T) {
+	f := FreshnessConfig{MaxReleasesBehind: 2, Decay: 0.5}
+	latest := map[string]int{"chrome": 124}
+	tests := []struct {
+		ua   string
+		want float64
+	}{
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", 1},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36", 1},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", 0.25},
+		{"Mozilla/5.0 (compatible; unknown)", 1},
+	}
+	for _, tt := range tests {
+		if got := f.score(UserAgent{UA: tt.ua}, latest); got != tt.want {
+			t.Errorf("score(%q) = %v, want %v", tt.ua, got, tt.want)
+		}
+	}
+}
+
+func TestGetFreshRandomDesktop(t *testing.T) {
+	if GetFreshRandomDesktop().UA == "" {
+		t.Errorf("GetFreshRandomDesktop returned an empty user agent")
+	}
+	if GetFreshRandomMobile().UA == "" {
+		t.Errorf("GetFreshRandomMobile returned an empty user agent")
+	}
+}
+
+func TestNewFreshWeights(t *testing.T) {
+	f := FreshnessConfig{MaxReleasesBehind: 0, Decay: 0.5}
+	latest := map[string]int{"chrome": 124}
+	agents := []UserAgent{
+		{UA: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", Pct: 10},
+		{UA: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36", Pct: 10},
+		{UA: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36", Pct: 10, Deprecated: true},
+	}
+	weights := newFreshWeights(f, latest, agents)
+	want := cumulativeWeights{10, 15, 15}
+	if len(weights) != len(want) {
+		t.Fatalf("newFreshWeights = %v, want %v", weights, want)
+	}
+	for i := range want {
+		if weights[i] != want[i] {
+			t.Fatalf("newFreshWeights = %v, want %v", weights, want)
+		}
+	}
+}
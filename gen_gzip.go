@@ -0,0 +1,40 @@
+//go:build ignore
+
+// gen_gzip writes the gzip-compressed copies of the datasets that are
+// embedded by default. Run it with go generate after editing a dataset.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"log"
+	"os"
+)
+
+var datasets = []string{
+	"desktop_useragents.json",
+	"mobile_useragents.json",
+	"tv_useragents.json",
+	"console_useragents.json",
+}
+
+func main() {
+	for _, name := range datasets {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var buf bytes.Buffer
+		zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			log.Fatal(err)
+		}
+		zw.Write(data)
+		if err := zw.Close(); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(name+".gz", buf.Bytes(), 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
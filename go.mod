@@ -1,3 +1,3 @@
 module github.com/baditaflorin/commonuseragent
 
-go 1.22.2
+go 1.23.0
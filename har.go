@@ -0,0 +1,158 @@
+package commonuseragent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// harFile mirrors the subset of the HAR 1.2 format needed to pull the
+// request headers out of every captured request.
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Headers []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"headers"`
+			} `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// HARProfile is a user agent imported from a HAR file together with the
+// headers the browser sent alongside it.
+type HARProfile struct {
+	Agent UserAgent
+	// Headers holds the Accept, Accept-Encoding, Accept-Language and
+	// Sec-CH-UA* headers of the agent's requests. Each header has the value
+	// the agent sent most often, since it varies with the kind of resource
+	// requested.
+	Headers http.Header
+}
+
+// isProfileHeader reports whether the header called name belongs in a
+// HARProfile.
+func isProfileHeader(name string) bool {
+	switch strings.ToLower(name) {
+	case "accept", "accept-encoding", "accept-language":
+		return true
+	}
+	return strings.HasPrefix(strings.ToLower(name), "sec-ch-ua")
+}
+
+// harTally counts the requests and header values seen for one user agent.
+type harTally struct {
+	requests int
+	// values counts each value of each profile header; order records the
+	// values in the order they were first seen to break ties.
+	values map[string]map[string]int
+	order  map[string][]string
+}
+
+func (t *harTally) add(name, value string) {
+	key := http.CanonicalHeaderKey(name)
+	if t.values[key] == nil {
+		t.values[key] = make(map[string]int)
+	}
+	if t.values[key][value] == 0 {
+		t.order[key] = append(t.order[key], value)
+	}
+	t.values[key][value]++
+}
+
+// headers returns the most common value of every header in t.
+func (t *harTally) headers() http.Header {
+	h := make(http.Header, len(t.order))
+	for key, values := range t.order {
+		best := values[0]
+		for _, v := range values[1:] {
+			if t.values[key][v] > t.values[key][best] {
+				best = v
+			}
+		}
+		h.Set(key, best)
+	}
+	return h
+}
+
+// ImportHAR reads a HAR file captured from a real browser and returns the
+// user agents it contains in the same format as the embedded datasets. The
+// Pct of each agent is its share of the captured requests, so the result can
+// be written out as a custom dataset that mirrors an actual browser population.
+// Use ImportHARProfiles to keep the other headers each agent sent.
+func ImportHAR(r io.Reader) ([]UserAgent, error) {
+	profiles, err := ImportHARProfiles(r)
+	if err != nil {
+		return nil, err
+	}
+	agents := make([]UserAgent, len(profiles))
+	for i, p := range profiles {
+		agents[i] = p.Agent
+	}
+	return agents, nil
+}
+
+// ImportHARProfiles is like ImportHAR but also returns the Accept,
+// Accept-Encoding, Accept-Language and client hint headers captured with
+// each agent, so a replayed request can carry the same header profile as the
+// browser it imitates.
+func ImportHARProfiles(r io.Reader) ([]HARProfile, error) {
+	var har harFile
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, fmt.Errorf("decode HAR: %w", err)
+	}
+
+	tallies := make(map[string]*harTally)
+	total := 0
+	for _, entry := range har.Log.Entries {
+		headers := entry.Request.Headers
+		ua := ""
+		for _, header := range headers {
+			if strings.EqualFold(header.Name, "User-Agent") {
+				ua = CanonicalizeUA(header.Value)
+				break
+			}
+		}
+		if ua == "" {
+			continue
+		}
+		t, ok := tallies[ua]
+		if !ok {
+			t = &harTally{values: make(map[string]map[string]int), order: make(map[string][]string)}
+			tallies[ua] = t
+		}
+		t.requests++
+		total++
+		for _, header := range headers {
+			if isProfileHeader(header.Name) {
+				t.add(header.Name, header.Value)
+			}
+		}
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("HAR contains no User-Agent headers")
+	}
+
+	profiles := make([]HARProfile, 0, len(tallies))
+	for ua, t := range tallies {
+		pct := math.Round(float64(t.requests)/float64(total)*10000) / 100
+		profiles = append(profiles, HARProfile{
+			Agent:   UserAgent{UA: ua, Pct: pct},
+			Headers: t.headers(),
+		})
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		a, b := profiles[i].Agent, profiles[j].Agent
+		if a.Pct != b.Pct {
+			return a.Pct > b.Pct
+		}
+		return a.UA < b.UA
+	})
+	return profiles, nil
+}
@@ -0,0 +1,70 @@
+package commonuseragent
+
+import (
+	"strings"
+	"testing"
+)
+
+const testHAR = `{"log": {"entries": [
+	{"request": {"headers": [{"name": "user-agent", "value": "Mozilla/5.0 (X11; Linux x86_64) Firefox/125.0"}]}},
+	{"request": {"headers": [{"name": "User-Agent", "value": "Mozilla/5.0 (X11; Linux x86_64) Firefox/125.0"}]}},
+	{"request": {"headers": [{"name": "User-Agent", "value": "Mozilla/5.0 (Windows NT 10.0) Chrome/124.0.0.0"}]}},
+	{"request": {"headers": [{"name": "Accept", "value": "*/*"}]}}
+]}}`
+
+func TestImportHAR(t *testing.T) {
+	agents, err := ImportHAR(strings.NewReader(testHAR))
+	if err != nil {
+		t.Fatalf("ImportHAR returned an error: %v", err)
+	}
+	if len(agents) != 2 {
+		t.Fatalf("ImportHAR returned %d agents, want 2", len(agents))
+	}
+	if agents[0].UA != "Mozilla/5.0 (X11; Linux x86_64) Firefox/125.0" || agents[0].Pct != 66.67 {
+		t.Errorf("ImportHAR returned unexpected first agent: %+v", agents[0])
+	}
+	if agents[1].Pct != 33.33 {
+		t.Errorf("ImportHAR returned unexpected second agent: %+v", agents[1])
+	}
+}
+
+func TestImportHARNoUserAgents(t *testing.T) {
+	if _, err := ImportHAR(strings.NewReader(`{"log": {"entries": []}}`)); err == nil {
+		t.Errorf("ImportHAR accepted a HAR without user agents")
+	}
+}
+
+func TestImportHARProfiles(t *testing.T) {
+	const chrome = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	har := `{"log": {"entries": [
+		{"request": {"headers": [
+			{"name": "User-Agent", "value": "` + chrome + `"},
+			{"name": "accept", "value": "text/html"},
+			{"name": "Accept-Language", "value": "en-US,en;q=0.9"},
+			{"name": "sec-ch-ua-platform", "value": "\"Windows\""},
+			{"name": "Cookie", "value": "secret"}
+		]}},
+		{"request": {"headers": [
+			{"name": "User-Agent", "value": "` + chrome + `"},
+			{"name": "Accept", "value": "image/avif"}
+		]}},
+		{"request": {"headers": [
+			{"name": "User-Agent", "value": "` + chrome + `"},
+			{"name": "Accept", "value": "image/avif"}
+		]}}
+	]}}`
+	profiles, err := ImportHARProfiles(strings.NewReader(har))
+	if err != nil {
+		t.Fatalf("ImportHARProfiles returned an error: %v", err)
+	}
+	if len(profiles) != 1 || profiles[0].Agent.UA != chrome || profiles[0].Agent.Pct != 100 {
+		t.Fatalf("ImportHARProfiles returned %+v", profiles)
+	}
+	h := profiles[0].Headers
+	if h.Get("Accept") != "image/avif" || h.Get("Accept-Language") != "en-US,en;q=0.9" || h.Get("Sec-CH-UA-Platform") != `"Windows"` {
+		t.Errorf("unexpected header profile: %v", h)
+	}
+	if h.Get("Cookie") != "" {
+		t.Errorf("profile kept a header outside the profile: %v", h)
+	}
+}
@@ -0,0 +1,35 @@
+package commonuseragent
+
+import "iter"
+
+// Desktop returns an iterator over the desktop agents. Unlike GetAllDesktop
+// it does not copy the dataset: it walks the snapshot current when Desktop
+// was called, which later reloads and edits leave untouched.
+func (m *Manager) Desktop() iter.Seq[UserAgent] {
+	return agentSeq(m.snap.Load().desktop)
+}
+
+// Mobile returns an iterator over the mobile agents. See Desktop.
+func (m *Manager) Mobile() iter.Seq[UserAgent] {
+	return agentSeq(m.snap.Load().mobile)
+}
+
+func agentSeq(agents []UserAgent) iter.Seq[UserAgent] {
+	return func(yield func(UserAgent) bool) {
+		for _, agent := range agents {
+			if !yield(agent) {
+				return
+			}
+		}
+	}
+}
+
+// Desktop returns an iterator over the desktop agents.
+func Desktop() iter.Seq[UserAgent] {
+	return Default().Desktop()
+}
+
+// Mobile returns an iterator over the mobile agents.
+func Mobile() iter.Seq[UserAgent] {
+	return Default().Mobile()
+}
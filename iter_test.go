@@ -0,0 +1,36 @@
+package commonuseragent
+
+import "testing"
+
+func TestIterators(t *testing.T) {
+	all := GetAllDesktop()
+	i := 0
+	for agent := range Desktop() {
+		if agent != all[i] {
+			t.Fatalf("Desktop() yielded %q at %d, want %q", agent.UA, i, all[i].UA)
+		}
+		i++
+	}
+	if i != len(all) {
+		t.Errorf("Desktop() yielded %d agents, want %d", i, len(all))
+	}
+
+	n := 0
+	for range Mobile() {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("Mobile() stopped after %d agents", n)
+	}
+}
+
+func BenchmarkDesktopIterator(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for range Desktop() {
+		}
+	}
+}
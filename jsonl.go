@@ -0,0 +1,30 @@
+package commonuseragent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// LoadJSONL reads a newline-delimited JSON dataset, one UserAgent object per
+// line. Records are decoded one at a time so the input is never held in
+// memory as a whole, and repeated metadata strings are shared between
+// records, which keeps large custom datasets cheap to load.
+func LoadJSONL(r io.Reader) ([]UserAgent, error) {
+	var agents []UserAgent
+	interned := make(metadataInterner)
+	dec := json.NewDecoder(r)
+	for {
+		var agent UserAgent
+		err := dec.Decode(&agent)
+		if errors.Is(err, io.EOF) {
+			return agents, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode JSONL record %d: %w", len(agents)+1, err)
+		}
+		interned.intern(&agent)
+		agents = append(agents, agent)
+	}
+}
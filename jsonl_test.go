@@ -0,0 +1,42 @@
+package commonuseragent
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestLoadJSONL(t *testing.T) {
+	input := `{"ua": "Mozilla/5.0 (X11; Linux x86_64) Firefox/125.0", "pct": 60}
+{"ua": "Mozilla/5.0 (Windows NT 10.0) Chrome/124.0.0.0", "pct": 40}
+`
+	agents, err := LoadJSONL(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("LoadJSONL returned an error: %v", err)
+	}
+	if len(agents) != 2 || agents[1].Pct != 40 {
+		t.Errorf("LoadJSONL returned unexpected agents: %+v", agents)
+	}
+}
+
+func TestLoadJSONLInvalid(t *testing.T) {
+	input := `{"ua": "Mozilla/5.0 (X11; Linux x86_64) Firefox/125.0", "pct": 60}
+{"ua": `
+	if _, err := LoadJSONL(strings.NewReader(input)); err == nil {
+		t.Errorf("LoadJSONL accepted a truncated record")
+	}
+}
+
+func TestLoadJSONLInternsMetadata(t *testing.T) {
+	input := `{"ua": "Mozilla/5.0 (X11; Linux x86_64) Firefox/125.0", "pct": 60, "browser": "Firefox", "os": "Linux"}
+{"ua": "Mozilla/5.0 (X11; Linux x86_64) Firefox/124.0", "pct": 40, "browser": "Firefox", "os": "Linux"}
+`
+	agents, err := LoadJSONL(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("LoadJSONL returned an error: %v", err)
+	}
+	if unsafe.StringData(agents[0].Browser) != unsafe.StringData(agents[1].Browser) ||
+		unsafe.StringData(agents[0].OS) != unsafe.StringData(agents[1].OS) {
+		t.Errorf("LoadJSONL kept separate copies of repeated metadata")
+	}
+}
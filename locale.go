@@ -0,0 +1,145 @@
+package commonuseragent
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidLocale is returned for locale strings that are not a language
+// code optionally followed by a region, such as "de" or "de-DE".
+var ErrInvalidLocale = errors.New("invalid locale")
+
+var localeTagPattern = regexp.MustCompile(`^([A-Za-z]{2,3})(?:[-_]([A-Za-z]{2}))?$`)
+
+// Profile is a user agent together with the locale headers and hints a
+// browser in that locale would send, for crawling through a proxy in a given
+// region without headers that contradict each other.
+type Profile struct {
+	Agent UserAgent
+	// Locale is the normalised locale, such as "de-DE".
+	Locale string
+	// AcceptLanguage is the Accept-Language header value, formatted the way
+	// the agent's browser formats it.
+	AcceptLanguage string
+	// Languages is what navigator.languages would report.
+	Languages []string
+	// TimeZone is a representative IANA time zone for the locale's region,
+	// or "" if the region is not known.
+	TimeZone string
+}
+
+// regionTimeZones maps regions to their most populous IANA time zone.
+var regionTimeZones = map[string]string{
+	"AR": "America/Argentina/Buenos_Aires",
+	"AT": "Europe/Vienna",
+	"AU": "Australia/Sydney",
+	"BE": "Europe/Brussels",
+	"BR": "America/Sao_Paulo",
+	"CA": "America/Toronto",
+	"CH": "Europe/Zurich",
+	"CN": "Asia/Shanghai",
+	"CZ": "Europe/Prague",
+	"DE": "Europe/Berlin",
+	"DK": "Europe/Copenhagen",
+	"ES": "Europe/Madrid",
+	"FI": "Europe/Helsinki",
+	"FR": "Europe/Paris",
+	"GB": "Europe/London",
+	"IE": "Europe/Dublin",
+	"IN": "Asia/Kolkata",
+	"IT": "Europe/Rome",
+	"JP": "Asia/Tokyo",
+	"KR": "Asia/Seoul",
+	"MX": "America/Mexico_City",
+	"NL": "Europe/Amsterdam",
+	"NO": "Europe/Oslo",
+	"PL": "Europe/Warsaw",
+	"PT": "Europe/Lisbon",
+	"RO": "Europe/Bucharest",
+	"RU": "Europe/Moscow",
+	"SE": "Europe/Stockholm",
+	"TR": "Europe/Istanbul",
+	"UA": "Europe/Kyiv",
+	"US": "America/New_York",
+}
+
+// GetRandomProfileForLocale returns a random desktop or mobile agent with an
+// Accept-Language header, navigator.languages and time zone matching locale,
+// such as "de-DE". Agents whose UA advertises a different locale are skipped.
+// English is added as a fallback language, as browsers installed in most
+// locales do.
+func (m *Manager) GetRandomProfileForLocale(locale string) (Profile, error) {
+	start := m.start()
+	match := localeTagPattern.FindStringSubmatch(locale)
+	if match == nil {
+		return Profile{}, fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+	}
+	lang, region := strings.ToLower(match[1]), strings.ToUpper(match[2])
+	tag := lang
+	if region != "" {
+		tag += "-" + region
+	}
+
+	s := m.snap.Load()
+	var candidates []UserAgent
+	for _, list := range [][]UserAgent{s.activeDesktop, s.activeMobile} {
+		for _, agent := range list {
+			l := uaLocale(agent.UA)
+			if l == "" || strings.EqualFold(strings.ReplaceAll(l, "_", "-"), tag) {
+				candidates = append(candidates, agent)
+			}
+		}
+	}
+	agent := s.pick(m.rnd, candidates)
+	if agent.UA == "" {
+		return Profile{}, fmt.Errorf("locale %q: %w", tag, ErrNoMatch)
+	}
+
+	languages := []string{tag}
+	if region != "" {
+		languages = append(languages, lang)
+	}
+	if lang != "en" {
+		languages = append(languages, "en-US", "en")
+	} else if region != "US" {
+		languages = append(languages, "en-US")
+	}
+
+	return Profile{
+		Agent:          m.served(start, strategyLocale, "", agent),
+		Locale:         tag,
+		AcceptLanguage: acceptLanguage(agent.Browser, languages),
+		Languages:      languages,
+		TimeZone:       regionTimeZones[region],
+	}, nil
+}
+
+// acceptLanguage formats languages as an Accept-Language value with the
+// quality values browser uses: Chromium and Safari step down by 0.1, while
+// Firefox spreads them evenly between 1 and 0.
+func acceptLanguage(browser string, languages []string) string {
+	var sb strings.Builder
+	for i, l := range languages {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(l)
+		if i == 0 {
+			continue
+		}
+		q := 1 - 0.1*float64(i)
+		if browser == "Firefox" {
+			q = 1 - float64(i)/float64(len(languages))
+		}
+		fmt.Fprintf(&sb, ";q=%.1f", max(q, 0.1))
+	}
+	return sb.String()
+}
+
+// GetRandomProfileForLocale returns an agent with locale headers matching
+// locale. See Manager.GetRandomProfileForLocale.
+func GetRandomProfileForLocale(locale string) (Profile, error) {
+	return Default().GetRandomProfileForLocale(locale)
+}
@@ -0,0 +1,44 @@
+package commonuseragent
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetRandomProfileForLocale(t *testing.T) {
+	p, err := GetRandomProfileForLocale("de_de")
+	if err != nil {
+		t.Fatalf("GetRandomProfileForLocale returned an error: %v", err)
+	}
+	if p.Agent.UA == "" || p.Locale != "de-DE" || p.TimeZone != "Europe/Berlin" {
+		t.Errorf("GetRandomProfileForLocale(de_de) = %+v", p)
+	}
+	if strings.Join(p.Languages, ",") != "de-DE,de,en-US,en" {
+		t.Errorf("Languages = %q", p.Languages)
+	}
+	if !strings.HasPrefix(p.AcceptLanguage, "de-DE,de;q=") {
+		t.Errorf("AcceptLanguage = %q", p.AcceptLanguage)
+	}
+
+	if _, err := GetRandomProfileForLocale("german"); !errors.Is(err, ErrInvalidLocale) {
+		t.Errorf("GetRandomProfileForLocale(german) error = %v, want ErrInvalidLocale", err)
+	}
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		browser   string
+		languages []string
+		want      string
+	}{
+		{"Chrome", []string{"de-DE", "de", "en-US", "en"}, "de-DE,de;q=0.9,en-US;q=0.8,en;q=0.7"},
+		{"Firefox", []string{"de", "en-US", "en"}, "de,en-US;q=0.7,en;q=0.3"},
+		{"Safari", []string{"en-US"}, "en-US"},
+	}
+	for _, tt := range tests {
+		if got := acceptLanguage(tt.browser, tt.languages); got != tt.want {
+			t.Errorf("acceptLanguage(%q, %q) = %q, want %q", tt.browser, tt.languages, got, tt.want)
+		}
+	}
+}
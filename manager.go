@@ -0,0 +1,292 @@
+package commonuseragent
+
+import (
+	"io"
+	"io/fs"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config holds the settings used to build a Manager.
+type Config struct {
+	Validation ValidationConfig
+	Freshness  FreshnessConfig
+	Duplicates DuplicatePolicy
+	// ReplayLog, if set, receives a line for every agent served.
+	ReplayLog io.Writer
+	// Recipes are named selections available through GetByRecipe.
+	Recipes map[string]Recipe
+	// SessionTTL is how long GetSessionUA keeps a session's agent.
+	SessionTTL time.Duration
+	// Rand, if set, replaces crypto/rand as the source of randomness.
+	Rand io.Reader
+	// FastRand selects the non-cryptographic math/rand/v2 generator.
+	FastRand bool
+	// ExcludePatterns drop matching agents at load time.
+	ExcludePatterns []string
+	// Observer, if set, is called for every agent served.
+	Observer func(SelectionEvent)
+	// NormalizeWeights scales each dataset's Pct values to sum to 100.
+	NormalizeWeights bool
+}
+
+// DefaultConfig returns the configuration used by the package-level functions.
+func DefaultConfig() Config {
+	return Config{
+		Validation: DefaultValidationConfig(),
+		Freshness:  DefaultFreshnessConfig(),
+	}
+}
+
+// Option customises the Config used by NewManager.
+type Option func(*Config)
+
+// WithConfig replaces the whole configuration.
+func WithConfig(cfg Config) Option {
+	return func(c *Config) {
+		*c = cfg
+	}
+}
+
+// WithValidation sets the validation limits and rules applied at load time.
+func WithValidation(v ValidationConfig) Option {
+	return func(c *Config) {
+		c.Validation = v
+	}
+}
+
+// Manager holds desktop and mobile user agents and selects from them. It is
+// safe for concurrent use: readers load an immutable snapshot of the
+// datasets without taking a lock, and writers publish a new snapshot.
+type Manager struct {
+	// mu serialises writers; readers never take it.
+	mu   sync.Mutex
+	snap atomic.Pointer[snapshot]
+	cfg  Config
+	rnd  randSource
+	// fsys is where the datasets were loaded from, used by Reload. It is nil
+	// for Managers built from readers.
+	fsys fs.FS
+	// stamp identifies the dataset file versions last loaded from fsys.
+	stamp   string
+	info    atomic.Pointer[DatasetInfo]
+	exclude excludeFilter
+	// registered holds the categories added with RegisterCategory, which
+	// Reload keeps. It is guarded by mu.
+	registered map[string][]UserAgent
+	// deprecated holds the Deprecated flag set at runtime by Deprecate and
+	// Undeprecate, keyed by UA, so it is reapplied after Reload. It is
+	// guarded by mu.
+	deprecated map[string]bool
+	// sessions holds the state of GetSessionUA sessions under a TTL; it is
+	// guarded by sessionMu.
+	sessionMu    sync.Mutex
+	sessions     map[string]sessionState
+	sessionSweep time.Time
+	// readOnly is set on Managers returned by Snapshot, which refuse every
+	// change to their data.
+	readOnly bool
+
+	// replayMu serialises writes to cfg.ReplayLog; clones share it.
+	replayMu *sync.Mutex
+}
+
+// NewManager loads and validates the embedded datasets.
+func NewManager(opts ...Option) (*Manager, error) {
+	cfg := DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return NewManagerFromFS(content, cfg)
+}
+
+// NewManagerFromFS loads and validates the datasets found in fsys instead of
+// the embedded ones, so data can be updated without recompiling: use
+// os.DirFS for a directory on disk, a zip.Reader, or an embed.FS of your own.
+// fsys must contain desktop_useragents.json and mobile_useragents.json (or
+// their .jsonl equivalents) at its root. Start from DefaultConfig to keep the
+// default validation rules.
+func NewManagerFromFS(fsys fs.FS, cfg Config) (*Manager, error) {
+	stamp, _ := datasetStamp(fsys)
+	desktop, err := loadCategory(fsys, desktopFile, cfg.Validation)
+	if err != nil {
+		return nil, err
+	}
+	mobile, err := loadCategory(fsys, mobileFile, cfg.Validation)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := loadOptionalCategories(fsys, cfg.Validation)
+	if err != nil {
+		return nil, err
+	}
+	info, err := loadManifest(fsys)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newManager(cfg, desktop, mobile, categories)
+	if err != nil {
+		return nil, err
+	}
+	m.fsys = fsys
+	m.stamp = stamp
+	m.info.Store(&info)
+	return m, nil
+}
+
+// NewManagerFromReaders builds a Manager from desktop and mobile datasets
+// read from r, for applications that fetch their lists from a database or an
+// HTTP source. Both readers may use any schema version DecodeDataset
+// understands.
+func NewManagerFromReaders(desktop, mobile io.Reader, cfg Config) (*Manager, error) {
+	desktopAgents, err := readCategory("desktop", desktop, cfg.Validation)
+	if err != nil {
+		return nil, err
+	}
+	mobileAgents, err := readCategory("mobile", mobile, cfg.Validation)
+	if err != nil {
+		return nil, err
+	}
+	return newManager(cfg, desktopAgents, mobileAgents, nil)
+}
+
+// newManager builds a Manager from datasets that have already been
+// validated.
+func newManager(cfg Config, desktop, mobile []UserAgent, categories map[string][]UserAgent) (*Manager, error) {
+	exclude, err := compileExcludePatterns(cfg.ExcludePatterns)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manager{
+		cfg:      cfg,
+		rnd:      defaultPool,
+		exclude:  exclude,
+		replayMu: new(sync.Mutex),
+	}
+	switch {
+	case cfg.FastRand:
+		m.rnd = fastSource{}
+	case cfg.Rand != nil:
+		m.rnd = newReaderPool(cfg.Rand)
+	}
+	m.setDatasets(desktop, mobile, categories, nil)
+	return m, nil
+}
+
+// setDatasets installs validated datasets, applying exclusions, duplicate
+// handling, runtime deprecations, weight normalization and engine tagging,
+// and publishes them as a new snapshot. Duplicates are merged only within a
+// dataset; agents shared by desktop and mobile are reported, and each other
+// category is checked only within itself, since a tv or registered category
+// may share agents with desktop or mobile. prior holds the warnings found
+// when the datasets were loaded, for edits to data that has already been
+// deduped; the warnings of the new snapshot are prior plus any new ones. It
+// must be called with mu held (or before the Manager is shared).
+func (m *Manager) setDatasets(desktop, mobile []UserAgent, categories map[string][]UserAgent, prior []LoadWarning) {
+	desktop = m.exclude.apply(desktop)
+	mobile = m.exclude.apply(mobile)
+	sets := []dataset{
+		{name: "desktop", agents: &desktop},
+		{name: "mobile", agents: &mobile},
+	}
+	warnings := dedupe(m.cfg.Duplicates, sets...)
+	applyDeprecated(m.deprecated, desktop)
+	applyDeprecated(m.deprecated, mobile)
+
+	names := sortedCategoryNames(categories)
+	lists := make([][]UserAgent, len(names))
+	for i, name := range names {
+		lists[i] = m.exclude.apply(categories[name])
+		set := dataset{name: name, agents: &lists[i]}
+		warnings = append(warnings, dedupe(m.cfg.Duplicates, set)...)
+		applyDeprecated(m.deprecated, lists[i])
+		sets = append(sets, set)
+	}
+	if m.cfg.NormalizeWeights {
+		for _, set := range sets {
+			normalizeWeights(*set.agents)
+		}
+	}
+
+	tagged := make(map[string][]UserAgent, len(names))
+	for i, name := range names {
+		tagEngines(lists[i])
+		tagged[name] = lists[i]
+	}
+	tagEngines(desktop)
+	tagEngines(mobile)
+	m.snap.Store(newSnapshot(desktop, mobile, tagged, mergeWarnings(prior, warnings), m.cfg.Freshness))
+}
+
+// swap atomically replaces the desktop and mobile datasets, keeping any
+// other categories; concurrent readers see either the old or the new lists,
+// never a mix.
+func (m *Manager) swap(desktop, mobile []UserAgent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.snap.Load()
+	// Keep the warnings about the other categories, which are not reloaded.
+	var prior []LoadWarning
+	for _, w := range s.warnings {
+		if !slices.Contains(w.Sources, "desktop") && !slices.Contains(w.Sources, "mobile") {
+			prior = append(prior, w)
+		}
+	}
+	m.setDatasets(desktop, mobile, s.categories, prior)
+}
+
+// GetAllDesktop returns a copy of all desktop user agents.
+func (m *Manager) GetAllDesktop() []UserAgent {
+	s := m.snap.Load()
+	return append([]UserAgent(nil), s.desktop...)
+}
+
+// GetAllMobile returns a copy of all mobile user agents.
+func (m *Manager) GetAllMobile() []UserAgent {
+	s := m.snap.Load()
+	return append([]UserAgent(nil), s.mobile...)
+}
+
+// GetRandomDesktop returns a random desktop user agent.
+func (m *Manager) GetRandomDesktop() UserAgent {
+	s := m.snap.Load()
+	return m.served(m.start(), strategyUniform, "desktop", s.pick(m.rnd, s.activeDesktop))
+}
+
+// GetRandomMobile returns a random mobile user agent.
+func (m *Manager) GetRandomMobile() UserAgent {
+	s := m.snap.Load()
+	return m.served(m.start(), strategyUniform, "mobile", s.pick(m.rnd, s.activeMobile))
+}
+
+// GetRandomDesktopUA returns just the UA string of a random desktop user agent.
+func (m *Manager) GetRandomDesktopUA() string {
+	return m.GetRandomDesktop().UA
+}
+
+// GetRandomMobileUA returns just the UA string of a random mobile user agent.
+func (m *Manager) GetRandomMobileUA() string {
+	return m.GetRandomMobile().UA
+}
+
+// GetRandomUA returns the UA string of a random agent from the combined
+// desktop and mobile lists.
+func (m *Manager) GetRandomUA() string {
+	s := m.snap.Load()
+	return m.served(m.start(), strategyUniform, "", s.pick(m.rnd, s.activeDesktop, s.activeMobile)).UA
+}
+
+// concatAgents returns a new slice holding the agents of every list in order.
+func concatAgents(lists ...[]UserAgent) []UserAgent {
+	n := 0
+	for _, list := range lists {
+		n += len(list)
+	}
+	all := make([]UserAgent, 0, n)
+	for _, list := range lists {
+		all = append(all, list...)
+	}
+	return all
+}
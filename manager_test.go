@@ -0,0 +1,99 @@
+package commonuseragent
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNewManager(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	if len(m.GetAllDesktop()) == 0 || len(m.GetAllMobile()) == 0 {
+		t.Errorf("NewManager loaded an empty dataset")
+	}
+	if m.GetRandomUA() == "" {
+		t.Errorf("GetRandomUA returned an empty user agent")
+	}
+}
+
+func TestNewManagerValidationLimits(t *testing.T) {
+	_, err := NewManager(WithValidation(ValidationConfig{MaxLength: 20}))
+	if err == nil {
+		t.Errorf("NewManager accepted agents longer than MaxLength")
+	}
+}
+
+func TestValidationConfigValidate(t *testing.T) {
+	v := DefaultValidationConfig()
+	tests := []struct {
+		name  string
+		agent UserAgent
+		valid bool
+	}{
+		{"valid", UserAgent{UA: "Mozilla/5.0 (X11; Linux x86_64)", Pct: 1}, true},
+		{"too short", UserAgent{UA: "curl/8"}, false},
+		{"control char", UserAgent{UA: "Mozilla/5.0\n(X11; Linux x86_64)"}, false},
+		{"negative pct", UserAgent{UA: "Mozilla/5.0 (X11; Linux x86_64)", Pct: -1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(tt.agent)
+			if (err == nil) != tt.valid {
+				t.Errorf("Validate(%q) error = %v, want valid %v", tt.agent.UA, err, tt.valid)
+			}
+		})
+	}
+
+	short := ValidationConfig{MinLength: 4}
+	if err := short.Validate(UserAgent{UA: "curl/8"}); err != nil {
+		t.Errorf("Validate rejected an agent within custom limits: %v", err)
+	}
+}
+
+func TestNewManagerFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"desktop_useragents.json": &fstest.MapFile{Data: []byte(`[{"ua": "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", "pct": 100}]`)},
+		"mobile_useragents.jsonl": &fstest.MapFile{Data: []byte(`{"ua": "Mozilla/5.0 (Android 14; Mobile; rv:125.0) Gecko/125.0 Firefox/125.0", "pct": 100}` + "\n")},
+	}
+	m, err := NewManagerFromFS(fsys, DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromFS returned an error: %v", err)
+	}
+	if got := m.GetRandomDesktopUA(); got != "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0" {
+		t.Errorf("GetRandomDesktopUA returned %q", got)
+	}
+	if len(m.GetAllMobile()) != 1 {
+		t.Errorf("NewManagerFromFS did not load the JSONL mobile dataset")
+	}
+}
+
+func TestNewManagerFromFSMissingDataset(t *testing.T) {
+	fsys := fstest.MapFS{
+		"desktop_useragents.json": &fstest.MapFile{Data: []byte(`[]`)},
+	}
+	if _, err := NewManagerFromFS(fsys, DefaultConfig()); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("NewManagerFromFS error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestNewManagerFromReaders(t *testing.T) {
+	desktop := strings.NewReader(`[{"ua": "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", "pct": 100}]`)
+	mobile := strings.NewReader(`{"version": 2, "agents": [{"ua": "Mozilla/5.0 (Android 14; Mobile; rv:125.0) Gecko/125.0 Firefox/125.0", "pct": 100}]}`)
+	m, err := NewManagerFromReaders(desktop, mobile, DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromReaders returned an error: %v", err)
+	}
+	if m.GetRandomDesktopUA() == "" || m.GetRandomMobileUA() == "" {
+		t.Errorf("NewManagerFromReaders built a Manager with empty datasets")
+	}
+
+	_, err = NewManagerFromReaders(strings.NewReader(`[{"ua": "short", "pct": 1}]`), strings.NewReader(`[]`), DefaultConfig())
+	if !errors.Is(err, ErrInvalidUserAgent) {
+		t.Errorf("NewManagerFromReaders error = %v, want ErrInvalidUserAgent", err)
+	}
+}
@@ -0,0 +1,68 @@
+package commonuseragent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+)
+
+// manifestFile describes the datasets shipped next to it.
+const manifestFile = "manifest.json"
+
+// DatasetInfo describes where a Manager's datasets came from, so operators
+// can tell how stale their user agent data is.
+type DatasetInfo struct {
+	// Version is the dataset release, such as "2024.04", or "" if the
+	// datasets are not versioned.
+	Version string
+	// Collected is when the data was gathered. It is the zero time when the
+	// date is not known, as for the embedded datasets, whose upstream fetch
+	// date was not recorded.
+	Collected time.Time
+	// Source is where the data was collected from.
+	Source string
+}
+
+// loadManifest reads the manifest in fsys. A missing manifest is not an
+// error and yields the zero DatasetInfo.
+func loadManifest(fsys fs.FS) (DatasetInfo, error) {
+	data, err := fs.ReadFile(fsys, manifestFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DatasetInfo{}, nil
+	}
+	if err != nil {
+		return DatasetInfo{}, err
+	}
+	var raw struct {
+		Version   string `json:"version"`
+		Collected string `json:"collected"`
+		Source    string `json:"source"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return DatasetInfo{}, fmt.Errorf("%s: %w", manifestFile, err)
+	}
+	info := DatasetInfo{Version: raw.Version, Source: raw.Source}
+	if raw.Collected != "" {
+		if info.Collected, err = time.Parse(time.DateOnly, raw.Collected); err != nil {
+			return DatasetInfo{}, fmt.Errorf("%s: %w", manifestFile, err)
+		}
+	}
+	return info, nil
+}
+
+// DatasetInfo returns the manifest of the datasets the Manager last loaded
+// from its file system. It is the zero DatasetInfo for Managers built from
+// readers or from a file system without a manifest.json.
+func (m *Manager) DatasetInfo() DatasetInfo {
+	if info := m.info.Load(); info != nil {
+		return *info
+	}
+	return DatasetInfo{}
+}
+
+// GetDatasetInfo returns the manifest of the embedded datasets.
+func GetDatasetInfo() DatasetInfo {
+	return Default().DatasetInfo()
+}
@@ -0,0 +1,42 @@
+package commonuseragent
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestGetDatasetInfo(t *testing.T) {
+	info := GetDatasetInfo()
+	if info.Source != "https://useragents.me/" || !info.Collected.IsZero() {
+		t.Errorf("GetDatasetInfo() = %+v, want the embedded manifest", info)
+	}
+}
+
+func TestDatasetInfoFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"desktop_useragents.json": &fstest.MapFile{Data: []byte(`[{"ua": "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", "pct": 100}]`)},
+		"mobile_useragents.json":  &fstest.MapFile{Data: []byte(`[{"ua": "Mozilla/5.0 (Android 14; Mobile; rv:125.0) Gecko/125.0 Firefox/125.0", "pct": 100}]`)},
+	}
+	m, err := NewManagerFromFS(fsys, DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromFS returned an error: %v", err)
+	}
+	if info := m.DatasetInfo(); info != (DatasetInfo{}) {
+		t.Errorf("DatasetInfo() without a manifest = %+v, want zero", info)
+	}
+
+	fsys[manifestFile] = &fstest.MapFile{Data: []byte(`{"version": "2025.01", "collected": "2025-01-15", "source": "internal crawl"}`)}
+	if err := m.Reload(); err != nil {
+		t.Fatalf("Reload returned an error: %v", err)
+	}
+	want := DatasetInfo{Version: "2025.01", Collected: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Source: "internal crawl"}
+	if info := m.DatasetInfo(); info != want {
+		t.Errorf("DatasetInfo() = %+v, want %+v", info, want)
+	}
+
+	fsys[manifestFile] = &fstest.MapFile{Data: []byte(`{"collected": "January"}`)}
+	if err := m.Reload(); err == nil {
+		t.Errorf("Reload accepted a manifest with an invalid date")
+	}
+}
@@ -0,0 +1,63 @@
+package commonuseragent
+
+import "errors"
+
+// errNoAgents is the panic value of the Must functions when there is nothing
+// to select from.
+var errNoAgents = errors.New("commonuseragent: no user agents available")
+
+// must returns ua, panicking if it is empty. init is the error that left the
+// Manager empty, if known.
+func must(ua string, init error) string {
+	if ua != "" {
+		return ua
+	}
+	if init != nil {
+		panic(errors.Join(errNoAgents, init))
+	}
+	panic(errNoAgents)
+}
+
+// orFallback returns ua, or fallback if ua is empty.
+func orFallback(ua, fallback string) string {
+	if ua == "" {
+		return fallback
+	}
+	return ua
+}
+
+// MustGetRandomUA is like GetRandomUA but panics if there is no agent to
+// return.
+func (m *Manager) MustGetRandomUA() string {
+	return must(m.GetRandomUA(), nil)
+}
+
+// GetRandomUAOr is like GetRandomUA but returns fallback if there is no
+// agent to return.
+func (m *Manager) GetRandomUAOr(fallback string) string {
+	return orFallback(m.GetRandomUA(), fallback)
+}
+
+// MustGetRandomUA returns a random desktop or mobile UA string, panicking if
+// the embedded datasets failed to load.
+func MustGetRandomUA() string {
+	return must(GetRandomUA(), GetInitError())
+}
+
+// MustGetRandomDesktopUA returns a random desktop UA string, panicking if the
+// embedded datasets failed to load.
+func MustGetRandomDesktopUA() string {
+	return must(GetRandomDesktopUA(), GetInitError())
+}
+
+// MustGetRandomMobileUA returns a random mobile UA string, panicking if the
+// embedded datasets failed to load.
+func MustGetRandomMobileUA() string {
+	return must(GetRandomMobileUA(), GetInitError())
+}
+
+// GetRandomUAOr returns a random desktop or mobile UA string, or fallback if
+// the embedded datasets failed to load.
+func GetRandomUAOr(fallback string) string {
+	return orFallback(GetRandomUA(), fallback)
+}
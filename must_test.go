@@ -0,0 +1,30 @@
+package commonuseragent
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMustGetRandomUA(t *testing.T) {
+	if MustGetRandomUA() == "" || MustGetRandomDesktopUA() == "" || MustGetRandomMobileUA() == "" {
+		t.Errorf("Must functions returned an empty user agent")
+	}
+	if got := GetRandomUAOr("fallback"); got == "fallback" {
+		t.Errorf("GetRandomUAOr used the fallback with the embedded datasets loaded")
+	}
+
+	empty, err := NewManagerFromReaders(strings.NewReader(`[]`), strings.NewReader(`[]`), DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromReaders returned an error: %v", err)
+	}
+	if got := empty.GetRandomUAOr("fallback"); got != "fallback" {
+		t.Errorf("GetRandomUAOr on an empty Manager = %q, want fallback", got)
+	}
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, errNoAgents) {
+			t.Errorf("MustGetRandomUA on an empty Manager panicked with %v", err)
+		}
+	}()
+	empty.MustGetRandomUA()
+}
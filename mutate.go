@@ -0,0 +1,78 @@
+package commonuseragent
+
+// AddDesktop adds agent to the desktop dataset, so a service can inject a
+// newly observed UA without reloading. The agent is canonicalized and
+// validated like a loaded one; adding a UA that is already present follows
+// the Manager's DuplicatePolicy. Added agents are lost on Reload.
+func (m *Manager) AddDesktop(agent UserAgent) error {
+	return m.add("desktop", agent)
+}
+
+// AddMobile adds agent to the mobile dataset. See AddDesktop.
+func (m *Manager) AddMobile(agent UserAgent) error {
+	return m.add("mobile", agent)
+}
+
+func (m *Manager) add(name string, agent UserAgent) error {
+	agents := []UserAgent{agent}
+	canonicalizeAgents(agents)
+	migrateAgents(agents)
+	if m.readOnly {
+		return ErrReadOnly
+	}
+	if err := validateDataset(name, agents, m.cfg.Validation); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.snap.Load()
+	desktop, mobile := s.desktop, s.mobile
+	if name == "desktop" {
+		desktop = append(desktop[:len(desktop):len(desktop)], agents[0])
+	} else {
+		mobile = append(mobile[:len(mobile):len(mobile)], agents[0])
+	}
+	m.setDatasets(desktop, mobile, s.categories, s.warnings)
+	return nil
+}
+
+// Remove deletes every agent with the given UA string from all datasets,
+// including registered categories. It reports whether any agent was found.
+// Removal lasts until the next Reload, which brings back agents that are
+// still in the dataset files; use Deprecate to keep an agent out of random
+// selection across reloads.
+func (m *Manager) Remove(ua string) bool {
+	if m.readOnly {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	found := false
+	without := func(list []UserAgent) []UserAgent {
+		kept := make([]UserAgent, 0, len(list))
+		for _, agent := range list {
+			if agent.UA == ua {
+				found = true
+				continue
+			}
+			kept = append(kept, agent)
+		}
+		return kept
+	}
+
+	s := m.snap.Load()
+	categories := make(map[string][]UserAgent, len(s.categories))
+	for name, list := range s.categories {
+		categories[name] = without(list)
+	}
+	for name, list := range m.registered {
+		m.registered[name] = without(list)
+	}
+	desktop, mobile := without(s.desktop), without(s.mobile)
+	if found {
+		m.setDatasets(desktop, mobile, categories, s.warnings)
+	}
+	return found
+}
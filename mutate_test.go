@@ -0,0 +1,40 @@
+package commonuseragent
+
+import "testing"
+
+func TestAddAndRemove(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	before := m.GetAllDesktop()
+	ua := "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36"
+
+	if err := m.AddDesktop(UserAgent{UA: ua, Pct: 1}); err != nil {
+		t.Fatalf("AddDesktop returned an error: %v", err)
+	}
+	all := m.GetAllDesktop()
+	if len(all) != len(before)+1 {
+		t.Fatalf("AddDesktop left %d agents, want %d", len(all), len(before)+1)
+	}
+	added := all[len(all)-1]
+	if added.UA != ua || added.Engine != EngineBlink || added.Browser != "Chrome" {
+		t.Errorf("AddDesktop stored %+v", added)
+	}
+	if err := m.AddMobile(UserAgent{UA: "short"}); err == nil {
+		t.Errorf("AddMobile accepted an invalid agent")
+	}
+
+	if !m.Remove(ua) {
+		t.Errorf("Remove(%q) = false, want true", ua)
+	}
+	if got := len(m.GetAllDesktop()); got != len(before) {
+		t.Errorf("Remove left %d agents, want %d", got, len(before))
+	}
+	if m.Remove(ua) {
+		t.Errorf("Remove of a missing agent returned true")
+	}
+	if m.GetAllDesktop()[0] != before[0] {
+		t.Errorf("Remove changed other agents")
+	}
+}
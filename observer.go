@@ -0,0 +1,39 @@
+package commonuseragent
+
+import "time"
+
+// SelectionEvent describes one agent handed out by a Manager.
+type SelectionEvent struct {
+	Agent UserAgent
+	// Category is the dataset the agent was served from, such as "desktop",
+	// "mobile", CategoryTV or a registered category. When a selection spans
+	// several datasets and the agent is in more than one, it is the first
+	// of them in the order desktop, mobile, then the others by name.
+	Category string
+	// Device is the agent's device class, such as desktop, mobile or tv.
+	Device DeviceClass
+	// Strategy names how the agent was chosen: "uniform", "weighted",
+	// "fresh", "engine", "pair", "recipe", "session", "query" or "locale".
+	Strategy string
+	// Latency is how long the selection took.
+	Latency time.Duration
+}
+
+// WithObserver makes the Manager call fn for every agent it hands out, so
+// applications can feed selections into their own metrics. fn runs
+// synchronously on the selecting goroutine, possibly from many goroutines
+// at once, so it must be fast and safe for concurrent use.
+func WithObserver(fn func(SelectionEvent)) Option {
+	return func(c *Config) {
+		c.Observer = fn
+	}
+}
+
+// start returns the time a selection began, or the zero time when there is
+// no observer to report its latency to.
+func (m *Manager) start() time.Time {
+	if m.cfg.Observer == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
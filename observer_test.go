@@ -0,0 +1,58 @@
+package commonuseragent
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestWithObserver(t *testing.T) {
+	var mu sync.Mutex
+	var events []SelectionEvent
+	m, err := NewManager(WithObserver(func(e SelectionEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	}))
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+
+	ua := m.GetRandomUA()
+	m.GetWeightedRandomMobile()
+	m.GetRandomPair()
+	m.Query().Browser("Netscape").Random()
+
+	if len(events) != 4 {
+		t.Fatalf("observer saw %d events, want 4: %+v", len(events), events)
+	}
+	if events[0].Agent.UA != ua || events[0].Strategy != strategyUniform || events[0].Latency < 0 {
+		t.Errorf("first event = %+v", events[0])
+	}
+	if events[1].Strategy != strategyWeighted || events[1].Device == "" {
+		t.Errorf("second event = %+v", events[1])
+	}
+	if events[2].Strategy != strategyPair || events[3].Strategy != strategyPair {
+		t.Errorf("pair events = %+v, %+v", events[2], events[3])
+	}
+
+	wantCategory := "mobile"
+	for _, agent := range m.GetAllDesktop() {
+		if agent.UA == ua {
+			wantCategory = "desktop"
+		}
+	}
+	for i, want := range []string{wantCategory, "mobile", "desktop", "mobile"} {
+		if events[i].Category != want {
+			t.Errorf("event %d has category %q, want %q", i, events[i].Category, want)
+		}
+	}
+
+	// A registered category overlapping desktop is named by its own name.
+	if err := m.RegisterCategory("x", m.GetAllDesktop()[:1]); err != nil {
+		t.Fatalf("RegisterCategory returned an error: %v", err)
+	}
+	m.GetRandomFromCategory("x")
+	if got := events[len(events)-1].Category; got != "x" {
+		t.Errorf("GetRandomFromCategory(x) reported category %q", got)
+	}
+}
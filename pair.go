@@ -0,0 +1,122 @@
+package commonuseragent
+
+import (
+	"regexp"
+)
+
+// DevicePair is a desktop and a mobile agent that plausibly belong to the
+// same person: the same browser family, and the same locale where either
+// agent advertises one.
+type DevicePair struct {
+	Desktop UserAgent
+	Mobile  UserAgent
+}
+
+// localePattern matches the legacy "; en-US" style locale token some
+// browsers still include in the platform section.
+var localePattern = regexp.MustCompile(`; ([a-z]{2}(?:[-_][A-Za-z]{2})?)[;)]`)
+
+// uaLocale returns the locale advertised in ua, or "" if there is none.
+func uaLocale(ua string) string {
+	if m := localePattern.FindStringSubmatch(ua); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// pairFamily maps a browser family to the family used for matching devices,
+// folding the iOS builds of Chrome and Firefox into their desktop siblings.
+func pairFamily(ua string) (string, bool) {
+	family, _, ok := browserMajorVersion(ua)
+	if !ok {
+		return "", false
+	}
+	switch family {
+	case "chrome-ios":
+		return "chrome", true
+	case "firefox-ios":
+		return "firefox", true
+	}
+	return family, true
+}
+
+// pairIndex lists the desktop agents that have a plausible mobile
+// counterpart, with the matching mobile agents for each. Desktop agents with
+// the same browser family and locale share one mobile slice.
+type pairIndex struct {
+	desktops []UserAgent
+	mobiles  [][]UserAgent
+}
+
+// newPairIndex matches every non-deprecated desktop agent with the
+// non-deprecated mobile agents of the same browser family whose locale is
+// the same or unknown on either side. Each UA is parsed once, so building
+// the index is linear in the size of the datasets plus the distinct
+// family and locale combinations.
+func newPairIndex(desktop, mobile []UserAgent) pairIndex {
+	type key struct{ family, locale string }
+	type candidate struct {
+		agent  UserAgent
+		locale string
+	}
+	byFamily := make(map[string][]candidate)
+	for _, mob := range mobile {
+		if mob.Deprecated {
+			continue
+		}
+		if family, ok := pairFamily(mob.UA); ok {
+			byFamily[family] = append(byFamily[family], candidate{mob, uaLocale(mob.UA)})
+		}
+	}
+
+	var idx pairIndex
+	matches := make(map[key][]UserAgent)
+	for _, d := range desktop {
+		if d.Deprecated {
+			continue
+		}
+		family, ok := pairFamily(d.UA)
+		if !ok {
+			continue
+		}
+		k := key{family, uaLocale(d.UA)}
+		mobiles, seen := matches[k]
+		if !seen {
+			for _, c := range byFamily[family] {
+				if k.locale == "" || c.locale == "" || c.locale == k.locale {
+					mobiles = append(mobiles, c.agent)
+				}
+			}
+			matches[k] = mobiles
+		}
+		if len(mobiles) > 0 {
+			idx.desktops = append(idx.desktops, d)
+			idx.mobiles = append(idx.mobiles, mobiles)
+		}
+	}
+	return idx
+}
+
+// GetRandomPair returns a desktop and mobile agent from the same browser
+// family and locale, simulating a single user switching devices. It returns
+// the zero DevicePair if no desktop agent has a mobile counterpart.
+func (m *Manager) GetRandomPair() DevicePair {
+	start := m.start()
+	s := m.snap.Load()
+	if len(s.pairs.desktops) == 0 {
+		return DevicePair{}
+	}
+	i, ok := m.rnd.intn(len(s.pairs.desktops))
+	if !ok {
+		return DevicePair{}
+	}
+	return DevicePair{
+		Desktop: m.served(start, strategyPair, "desktop", s.pairs.desktops[i]),
+		Mobile:  m.served(start, strategyPair, "mobile", s.pick(m.rnd, s.pairs.mobiles[i])),
+	}
+}
+
+// GetRandomPair returns a matched desktop and mobile agent.
+func GetRandomPair() DevicePair {
+	return Default().GetRandomPair()
+}
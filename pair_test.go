@@ -0,0 +1,44 @@
+package commonuseragent
+
+import (
+	"testing"
+)
+
+func TestGetRandomPair(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		pair := GetRandomPair()
+		if pair.Desktop.UA == "" || pair.Mobile.UA == "" {
+			t.Fatalf("GetRandomPair returned an incomplete pair: %+v", pair)
+		}
+		df, _ := pairFamily(pair.Desktop.UA)
+		mf, _ := pairFamily(pair.Mobile.UA)
+		if df != mf {
+			t.Fatalf("GetRandomPair mixed browser families %q and %q", df, mf)
+		}
+	}
+}
+
+func TestPairIndexLocale(t *testing.T) {
+	desktop := UserAgent{UA: "Mozilla/5.0 (Windows; U; Windows NT 6.1; de-DE) AppleWebKit/533.20 (KHTML, like Gecko) Version/5.0.4 Safari/533.20"}
+	mobileDE := UserAgent{UA: "Mozilla/5.0 (iPhone; U; CPU iPhone OS 4_3 like Mac OS X; de-DE) AppleWebKit/533.17.9 (KHTML, like Gecko) Version/5.0.2 Mobile/8F190 Safari/6533.18.5"}
+	mobileFR := UserAgent{UA: "Mozilla/5.0 (iPhone; U; CPU iPhone OS 4_3 like Mac OS X; fr-FR) AppleWebKit/533.17.9 (KHTML, like Gecko) Version/5.0.2 Mobile/8F190 Safari/6533.18.5"}
+	mobileAny := UserAgent{UA: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"}
+	chrome := UserAgent{UA: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36"}
+
+	idx := newPairIndex([]UserAgent{desktop}, []UserAgent{mobileDE, mobileFR, mobileAny, chrome})
+	if len(idx.desktops) != 1 {
+		t.Fatalf("pair index holds %d desktop agents, want 1", len(idx.desktops))
+	}
+	got := idx.mobiles[0]
+	if len(got) != 2 || got[0].UA != mobileDE.UA || got[1].UA != mobileAny.UA {
+		t.Errorf("desktop de-DE Safari was paired with %+v, want the de-DE and locale-free Safari agents", got)
+	}
+}
+
+func BenchmarkGetRandomPair(b *testing.B) {
+	m := Default()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.GetRandomPair()
+	}
+}
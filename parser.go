@@ -0,0 +1,172 @@
+package commonuseragent
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// DeviceClass is the broad kind of device a user agent belongs to.
+type DeviceClass string
+
+// Device classes recognised by Parse.
+const (
+	DeviceUnknown DeviceClass = "unknown"
+	DeviceDesktop DeviceClass = "desktop"
+	DeviceMobile  DeviceClass = "mobile"
+	DeviceTablet  DeviceClass = "tablet"
+	DeviceBot     DeviceClass = "bot"
+	DeviceTV      DeviceClass = "tv"
+	DeviceConsole DeviceClass = "console"
+)
+
+// ErrUnrecognizedUA is returned by Parse when neither a browser nor an
+// operating system can be identified.
+var ErrUnrecognizedUA = errors.New("unrecognized user agent")
+
+// ParsedUA holds the details Parse extracts from a UA string. Fields that
+// cannot be determined are left empty.
+type ParsedUA struct {
+	Browser        string
+	BrowserVersion string
+	OS             string
+	OSVersion      string
+	Device         DeviceClass
+	Engine         Engine
+}
+
+// browserRules are tried in order; browsers built on Chrome or Safari must
+// come before them because they also carry those tokens.
+var browserRules = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"Edge", regexp.MustCompile(`Edg(?:e|A|iOS)?/([\d.]+)`)},
+	{"Opera", regexp.MustCompile(`(?:OPR|OPiOS)/([\d.]+)`)},
+	{"Samsung Internet", regexp.MustCompile(`SamsungBrowser/([\d.]+)`)},
+	{"Huawei Browser", regexp.MustCompile(`HuaweiBrowser/([\d.]+)`)},
+	{"Google App", regexp.MustCompile(`GSA/([\d.]+)`)},
+	{"Chrome", regexp.MustCompile(`CriOS/([\d.]+)`)},
+	{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/([\d.]+)`)},
+	{"Internet Explorer", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)([\d.]+)`)},
+	{"Chrome", regexp.MustCompile(`Chrome/([\d.]+)`)},
+	{"Safari", regexp.MustCompile(`Version/([\d.]+).*Safari`)},
+}
+
+var (
+	tvPattern      = regexp.MustCompile(`SMART-TV|SmartTV|Web0S|webOS\.TV|Tizen.* TV|HbbTV|\bAFT[A-Z]|CrKey/|BRAVIA|Roku/|Apple TV|GoogleTV|Android TV`)
+	consolePattern = regexp.MustCompile(`PlayStation|Xbox|XBOX|Nintendo`)
+	botPattern     = regexp.MustCompile(`(?i)bot\b|crawler|spider|slurp|facebookexternalhit|curl/|wget/|python-requests`)
+	windowsPattern = regexp.MustCompile(`Windows NT ([\d.]+)`)
+	iosPattern     = regexp.MustCompile(`(?:iPhone|CPU) OS ([\d_]+)`)
+	macPattern     = regexp.MustCompile(`Mac OS X ([\d_.]+)`)
+	androidPattern = regexp.MustCompile(`Android ([\d.]+)`)
+)
+
+// windowsVersions maps Windows NT kernel versions to marketing names.
+// Windows 11 still reports NT 10.0, so it cannot be told apart here.
+var windowsVersions = map[string]string{
+	"10.0": "10",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.1":  "XP",
+}
+
+// Parse extracts the browser, operating system and device class from any UA
+// string, so traffic can be classified with the same package that generates
+// it. It returns ErrUnrecognizedUA if nothing useful can be identified.
+func Parse(ua string) (ParsedUA, error) {
+	ua = CanonicalizeUA(ua)
+	p := ParsedUA{Device: DeviceUnknown, Engine: DetectEngine(ua)}
+	if ua == "" {
+		return p, ErrUnrecognizedUA
+	}
+
+	for _, rule := range browserRules {
+		if m := rule.pattern.FindStringSubmatch(ua); m != nil {
+			// Truncated UAs can end a version with a dot, as in "Firefox/125.".
+			p.Browser, p.BrowserVersion = rule.name, strings.TrimRight(m[1], ".")
+			break
+		}
+	}
+	p.OS, p.OSVersion = parseOS(ua)
+	p.Device = parseDevice(ua, p.OS)
+
+	if p.Browser == "" && p.OS == "" && p.Device == DeviceUnknown {
+		return p, ErrUnrecognizedUA
+	}
+	return p, nil
+}
+
+// ClassifyUA returns the device class of ua, such as DeviceDesktop,
+// DeviceMobile, DeviceTablet or DeviceBot, so servers can label the agents of
+// requests they receive with the same detection used for the datasets. It
+// skips the browser detection Parse does and returns DeviceUnknown when
+// nothing is recognised.
+func ClassifyUA(ua string) DeviceClass {
+	ua = CanonicalizeUA(ua)
+	if ua == "" {
+		return DeviceUnknown
+	}
+	os, _ := parseOS(ua)
+	return parseDevice(ua, os)
+}
+
+func parseOS(ua string) (name, version string) {
+	switch {
+	case strings.Contains(ua, "Windows"):
+		if m := windowsPattern.FindStringSubmatch(ua); m != nil {
+			if v, ok := windowsVersions[m[1]]; ok {
+				return "Windows", v
+			}
+			return "Windows", m[1]
+		}
+		return "Windows", ""
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"), strings.Contains(ua, "iPod"):
+		if m := iosPattern.FindStringSubmatch(ua); m != nil {
+			return "iOS", strings.ReplaceAll(m[1], "_", ".")
+		}
+		return "iOS", ""
+	case strings.Contains(ua, "Macintosh"):
+		if m := macPattern.FindStringSubmatch(ua); m != nil {
+			return "macOS", strings.ReplaceAll(m[1], "_", ".")
+		}
+		return "macOS", ""
+	case strings.Contains(ua, "Android"):
+		if m := androidPattern.FindStringSubmatch(ua); m != nil {
+			return "Android", m[1]
+		}
+		return "Android", ""
+	case strings.Contains(ua, "CrOS"):
+		return "ChromeOS", ""
+	case strings.Contains(ua, "Linux"), strings.Contains(ua, "X11"):
+		return "Linux", ""
+	}
+	return "", ""
+}
+
+func parseDevice(ua, os string) DeviceClass {
+	switch {
+	case botPattern.MatchString(ua):
+		return DeviceBot
+	case consolePattern.MatchString(ua):
+		return DeviceConsole
+	case tvPattern.MatchString(ua):
+		return DeviceTV
+	case strings.Contains(ua, "iPad"), strings.Contains(ua, "Tablet"):
+		return DeviceTablet
+	case os == "Android":
+		// Android phones advertise "Mobile"; tablets leave it out.
+		if strings.Contains(ua, "Mobile") {
+			return DeviceMobile
+		}
+		return DeviceTablet
+	case os == "iOS", strings.Contains(ua, "Mobile"):
+		return DeviceMobile
+	case os == "Windows", os == "macOS", os == "Linux", os == "ChromeOS":
+		return DeviceDesktop
+	}
+	return DeviceUnknown
+}
@@ -0,0 +1,111 @@
+package commonuseragent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want ParsedUA
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0",
+			ParsedUA{Browser: "Edge", BrowserVersion: "124.0.0.0", OS: "Windows", OSVersion: "10", Device: DeviceDesktop, Engine: EngineBlink},
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:125.0) Gecko/20100101 Firefox/125.0",
+			ParsedUA{Browser: "Firefox", BrowserVersion: "125.0", OS: "macOS", OSVersion: "10.15", Device: DeviceDesktop, Engine: EngineGecko},
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:125.0) Gecko/20100101 Firefox/125.",
+			ParsedUA{Browser: "Firefox", BrowserVersion: "125", OS: "macOS", OSVersion: "10.15", Device: DeviceDesktop, Engine: EngineGecko},
+		},
+		{
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4.1 Mobile/15E148 Safari/604.1",
+			ParsedUA{Browser: "Safari", BrowserVersion: "17.4.1", OS: "iOS", OSVersion: "17.4.1", Device: DeviceMobile, Engine: EngineWebKit},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/25.0 Chrome/121.0.0.0 Mobile Safari/537.36",
+			ParsedUA{Browser: "Samsung Internet", BrowserVersion: "25.0", OS: "Android", OSVersion: "10", Device: DeviceMobile, Engine: EngineBlink},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			ParsedUA{Browser: "Chrome", BrowserVersion: "124.0.0.0", OS: "Android", OSVersion: "13", Device: DeviceTablet, Engine: EngineBlink},
+		},
+		{
+			"Mozilla/5.0 (Windows NT 6.1; WOW64; Trident/7.0; rv:11.0) like Gecko",
+			ParsedUA{Browser: "Internet Explorer", BrowserVersion: "11.0", OS: "Windows", OSVersion: "7", Device: DeviceDesktop, Engine: EngineTrident},
+		},
+		{
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			ParsedUA{Device: DeviceBot},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.ua)
+		if err != nil {
+			t.Errorf("Parse(%q) returned an error: %v", tt.ua, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.ua, got, tt.want)
+		}
+	}
+}
+
+func TestParseUnrecognized(t *testing.T) {
+	for _, ua := range []string{"", "something else entirely"} {
+		if _, err := Parse(ua); !errors.Is(err, ErrUnrecognizedUA) {
+			t.Errorf("Parse(%q) error = %v, want ErrUnrecognizedUA", ua, err)
+		}
+	}
+}
+
+func TestParseEmbeddedDatasets(t *testing.T) {
+	for _, agent := range GetAllDesktop() {
+		if p, err := Parse(agent.UA); err != nil || p.Device != DeviceDesktop {
+			t.Errorf("Parse(%q) = %+v, %v; want a desktop agent", agent.UA, p, err)
+		}
+	}
+	for _, agent := range GetAllMobile() {
+		if p, err := Parse(agent.UA); err != nil || p.Device != DeviceMobile {
+			t.Errorf("Parse(%q) = %+v, %v; want a mobile agent", agent.UA, p, err)
+		}
+	}
+	for _, agent := range GetAllTV() {
+		if p, err := Parse(agent.UA); err != nil || p.Device != DeviceTV {
+			t.Errorf("Parse(%q) = %+v, %v; want a TV agent", agent.UA, p, err)
+		}
+	}
+	for _, agent := range GetAllConsole() {
+		if p, err := Parse(agent.UA); err != nil || p.Device != DeviceConsole {
+			t.Errorf("Parse(%q) = %+v, %v; want a console agent", agent.UA, p, err)
+		}
+	}
+}
+
+func TestClassifyUA(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want DeviceClass
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", DeviceDesktop},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4.1 Mobile/15E148 Safari/604.1", DeviceMobile},
+		{"Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1", DeviceTablet},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", DeviceBot},
+		{"", DeviceUnknown},
+		{"something else", DeviceUnknown},
+	}
+	for _, tt := range tests {
+		if got := ClassifyUA(tt.ua); got != tt.want {
+			t.Errorf("ClassifyUA(%q) = %q, want %q", tt.ua, got, tt.want)
+		}
+	}
+	for _, agent := range GetAllMobile() {
+		if p, _ := Parse(agent.UA); ClassifyUA(agent.UA) != p.Device {
+			t.Errorf("ClassifyUA and Parse disagree on %q", agent.UA)
+		}
+	}
+}
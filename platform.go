@@ -0,0 +1,41 @@
+package commonuseragent
+
+// GetRandomAndroidUA returns the UA string of a random Android agent.
+func (m *Manager) GetRandomAndroidUA() string {
+	return m.Query().OS("Android").Random().UA
+}
+
+// GetRandomIOSUA returns the UA string of a random iPhone or iPad agent.
+func (m *Manager) GetRandomIOSUA() string {
+	return m.Query().OS("iOS").Random().UA
+}
+
+// GetRandomWindowsUA returns the UA string of a random Windows agent.
+func (m *Manager) GetRandomWindowsUA() string {
+	return m.Query().OS("Windows").Random().UA
+}
+
+// GetRandomMacUA returns the UA string of a random macOS agent.
+func (m *Manager) GetRandomMacUA() string {
+	return m.Query().OS("macOS").Random().UA
+}
+
+// GetRandomAndroidUA returns the UA string of a random Android agent.
+func GetRandomAndroidUA() string {
+	return Default().GetRandomAndroidUA()
+}
+
+// GetRandomIOSUA returns the UA string of a random iPhone or iPad agent.
+func GetRandomIOSUA() string {
+	return Default().GetRandomIOSUA()
+}
+
+// GetRandomWindowsUA returns the UA string of a random Windows agent.
+func GetRandomWindowsUA() string {
+	return Default().GetRandomWindowsUA()
+}
+
+// GetRandomMacUA returns the UA string of a random macOS agent.
+func GetRandomMacUA() string {
+	return Default().GetRandomMacUA()
+}
@@ -0,0 +1,23 @@
+package commonuseragent
+
+import "testing"
+
+func TestPlatformGetters(t *testing.T) {
+	tests := []struct {
+		name string
+		get  func() string
+		os   string
+	}{
+		{"Android", GetRandomAndroidUA, "Android"},
+		{"IOS", GetRandomIOSUA, "iOS"},
+		{"Windows", GetRandomWindowsUA, "Windows"},
+		{"Mac", GetRandomMacUA, "macOS"},
+	}
+	for _, tt := range tests {
+		ua := tt.get()
+		p, err := Parse(ua)
+		if err != nil || p.OS != tt.os {
+			t.Errorf("GetRandom%sUA() = %q, parsed OS %q, want %q", tt.name, ua, p.OS, tt.os)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package commonuseragent
+
+import "strings"
+
+// Query is a fluent filter over a Manager's agents, built with Manager.Query:
+//
+//	agent := m.Query().OS("windows").Browser("firefox").MinPct(1).Random()
+//
+// String filters compare case-insensitively and unset filters match
+// anything. Deprecated agents are never returned. A Query is not safe for
+// concurrent use, but each terminal method reads the Manager's current data.
+type Query struct {
+	m        *Manager
+	category string
+	browser  string
+	os       string
+	engine   Engine
+	device   DeviceClass
+	minPct   float64
+}
+
+// Query starts a new Query over desktop and mobile agents.
+func (m *Manager) Query() *Query {
+	return &Query{m: m}
+}
+
+// Category limits the query to one dataset: "desktop", "mobile" or another
+// loaded or registered category. An unknown category matches nothing.
+func (q *Query) Category(name string) *Query {
+	q.category = name
+	return q
+}
+
+// Browser limits the query to a browser name as reported by Parse, such as
+// "Chrome" or "Samsung Internet".
+func (q *Query) Browser(name string) *Query {
+	q.browser = name
+	return q
+}
+
+// OS limits the query to an operating system as reported by Parse, such as
+// "Windows" or "Android".
+func (q *Query) OS(name string) *Query {
+	q.os = name
+	return q
+}
+
+// Engine limits the query to a rendering engine.
+func (q *Query) Engine(e Engine) *Query {
+	q.engine = e
+	return q
+}
+
+// Device limits the query to a device class.
+func (q *Query) Device(d DeviceClass) *Query {
+	q.device = d
+	return q
+}
+
+// MinPct drops agents whose usage share is below pct.
+func (q *Query) MinPct(pct float64) *Query {
+	q.minPct = pct
+	return q
+}
+
+// matches reports whether agent passes every filter.
+func (q *Query) matches(agent UserAgent) bool {
+	return !agent.Deprecated &&
+		(q.browser == "" || strings.EqualFold(q.browser, agent.Browser)) &&
+		(q.os == "" || strings.EqualFold(q.os, agent.OS)) &&
+		(q.engine == EngineUnknown || q.engine == agent.Engine) &&
+		(q.device == "" || q.device == agent.Device) &&
+		agent.Pct >= q.minPct
+}
+
+// match returns the agents in s that pass the filters. ok is false if the
+// category does not exist.
+func (q *Query) match(s *snapshot) (matched []UserAgent, ok bool) {
+	lists := [][]UserAgent{s.desktop, s.mobile}
+	if q.category != "" {
+		list, ok := s.category(q.category)
+		if !ok {
+			return nil, false
+		}
+		lists = [][]UserAgent{list}
+	}
+	for _, list := range lists {
+		for _, agent := range list {
+			if q.matches(agent) {
+				matched = append(matched, agent)
+			}
+		}
+	}
+	return matched, true
+}
+
+// All returns every matching agent.
+func (q *Query) All() []UserAgent {
+	matched, _ := q.match(q.m.snap.Load())
+	return matched
+}
+
+// Count returns how many agents match.
+func (q *Query) Count() int {
+	return len(q.All())
+}
+
+// Random returns a uniformly chosen matching agent, or the zero UserAgent if
+// nothing matches.
+func (q *Query) Random() UserAgent {
+	start := q.m.start()
+	s := q.m.snap.Load()
+	matched, _ := q.match(s)
+	return q.m.served(start, strategyQuery, q.category, s.pick(q.m.rnd, matched))
+}
+
+// RandomWeighted returns a matching agent chosen with probability
+// proportional to its Pct, or the zero UserAgent if nothing matches.
+func (q *Query) RandomWeighted() UserAgent {
+	start := q.m.start()
+	s := q.m.snap.Load()
+	matched, _ := q.match(s)
+	return q.m.served(start, strategyQuery, q.category, s.pickByWeight(q.m.rnd, newCumulativeWeights(matched), matched))
+}
+
+// NewQuery starts a new Query over the default Manager.
+func NewQuery() *Query {
+	return Default().Query()
+}
@@ -0,0 +1,45 @@
+package commonuseragent
+
+import "testing"
+
+func TestQuery(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+
+	all := m.Query().OS("windows").Browser("chrome").MinPct(1).All()
+	if len(all) == 0 {
+		t.Fatalf("Query for Windows Chrome found nothing")
+	}
+	for _, agent := range all {
+		if agent.OS != "Windows" || agent.Browser != "Chrome" || agent.Pct < 1 {
+			t.Errorf("Query returned non-matching agent %+v", agent)
+		}
+	}
+
+	if agent := m.Query().Category("mobile").OS("iOS").Random(); agent.OS != "iOS" || agent.Device != DeviceMobile {
+		t.Errorf("Query().Category(mobile).OS(iOS).Random() = %+v", agent)
+	}
+	if agent := m.Query().Engine(EngineGecko).RandomWeighted(); agent.Engine != EngineGecko {
+		t.Errorf("Query().Engine(gecko).RandomWeighted() = %+v", agent)
+	}
+	if n := m.Query().Category(CategoryTV).Device(DeviceTV).Count(); n != len(m.GetAllTV()) {
+		t.Errorf("Query over tv counted %d agents, want %d", n, len(m.GetAllTV()))
+	}
+
+	if agent := m.Query().Browser("Netscape").Random(); agent.UA != "" {
+		t.Errorf("Query with no matches returned %q", agent.UA)
+	}
+	if n := m.Query().Category("fridge").Count(); n != 0 {
+		t.Errorf("Query over an unknown category counted %d agents", n)
+	}
+
+	target := all[0].UA
+	m.Deprecate(target)
+	for _, agent := range m.Query().OS("windows").All() {
+		if agent.UA == target {
+			t.Errorf("Query returned deprecated agent %q", target)
+		}
+	}
+}
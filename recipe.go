@@ -0,0 +1,64 @@
+package commonuseragent
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownRecipe is returned by GetByRecipe for a name that was not
+// registered with WithRecipe.
+var ErrUnknownRecipe = errors.New("unknown recipe")
+
+// ErrNoMatch is returned when no user agent satisfies a selection's filters.
+var ErrNoMatch = errors.New("no user agent matches")
+
+// Recipe is a named, reusable selection so teams can share a profile such as
+// "mobile Chrome on Android" instead of repeating the filters everywhere.
+// Empty fields match anything; string fields compare case-insensitively.
+type Recipe struct {
+	// Category is "desktop", "mobile" or another loaded category. Empty
+	// means desktop and mobile combined.
+	Category string
+	Browser  string
+	OS       string
+	Engine   Engine
+	// Weighted picks by Pct instead of uniformly.
+	Weighted bool
+}
+
+// WithRecipe registers a named recipe for use with GetByRecipe.
+func WithRecipe(name string, r Recipe) Option {
+	return func(c *Config) {
+		if c.Recipes == nil {
+			c.Recipes = make(map[string]Recipe)
+		}
+		c.Recipes[name] = r
+	}
+}
+
+// GetByRecipe returns an agent selected by the recipe registered under name.
+func (m *Manager) GetByRecipe(name string) (UserAgent, error) {
+	start := m.start()
+	r, ok := m.cfg.Recipes[name]
+	if !ok {
+		return UserAgent{}, fmt.Errorf("%w: %q", ErrUnknownRecipe, name)
+	}
+
+	s := m.snap.Load()
+	q := m.Query().Category(r.Category).Browser(r.Browser).OS(r.OS).Engine(r.Engine)
+	matched, ok := q.match(s)
+	if !ok {
+		return UserAgent{}, fmt.Errorf("recipe %q: %w: %q", name, ErrUnknownCategory, r.Category)
+	}
+
+	var agent UserAgent
+	if r.Weighted {
+		agent = s.pickByWeight(m.rnd, newCumulativeWeights(matched), matched)
+	} else {
+		agent = s.pick(m.rnd, matched)
+	}
+	if agent.UA == "" {
+		return UserAgent{}, fmt.Errorf("recipe %q: %w", name, ErrNoMatch)
+	}
+	return m.served(start, strategyRecipe, r.Category, agent), nil
+}
@@ -0,0 +1,37 @@
+package commonuseragent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetByRecipe(t *testing.T) {
+	m, err := NewManager(
+		WithRecipe("mobile-samsung", Recipe{Category: "mobile", Browser: "samsung internet", Weighted: true}),
+		WithRecipe("windows-firefox", Recipe{Browser: "Firefox", OS: "Windows"}),
+		WithRecipe("mobile-trident", Recipe{Category: "mobile", Engine: EngineTrident}),
+		WithRecipe("watches", Recipe{Category: "smartwatch"}),
+	)
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+
+	agent, err := m.GetByRecipe("mobile-samsung")
+	if err != nil || agent.Browser != "Samsung Internet" || agent.Device != DeviceMobile {
+		t.Errorf("GetByRecipe(mobile-samsung) = %+v, %v", agent, err)
+	}
+	agent, err = m.GetByRecipe("windows-firefox")
+	if err != nil || agent.Browser != "Firefox" || agent.OS != "Windows" {
+		t.Errorf("GetByRecipe(windows-firefox) = %+v, %v", agent, err)
+	}
+
+	if _, err := m.GetByRecipe("mobile-trident"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("GetByRecipe(mobile-trident) error = %v, want ErrNoMatch", err)
+	}
+	if _, err := m.GetByRecipe("watches"); !errors.Is(err, ErrUnknownCategory) {
+		t.Errorf("GetByRecipe(watches) error = %v, want ErrUnknownCategory", err)
+	}
+	if _, err := m.GetByRecipe("missing"); !errors.Is(err, ErrUnknownRecipe) {
+		t.Errorf("GetByRecipe(missing) error = %v, want ErrUnknownRecipe", err)
+	}
+}
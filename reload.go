@@ -0,0 +1,143 @@
+package commonuseragent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrReloadUnsupported is returned by Reload and Watch for a Manager that was
+// not built from an fs.FS, such as one created by NewManagerFromReaders.
+var ErrReloadUnsupported = errors.New("manager has no reloadable source")
+
+// Reload reads the Manager's datasets again from the fs.FS it was built from
+// and swaps them in atomically, so concurrent readers never see a
+// half-loaded list. If loading or validation fails the current data is kept
+// and the error is returned.
+func (m *Manager) Reload() error {
+	if m.fsys == nil {
+		return ErrReloadUnsupported
+	}
+	// Stamp before loading so a write racing with the load is picked up by
+	// the next Watch poll rather than missed.
+	stamp, _ := datasetStamp(m.fsys)
+	desktop, err := loadCategory(m.fsys, desktopFile, m.cfg.Validation)
+	if err != nil {
+		return err
+	}
+	mobile, err := loadCategory(m.fsys, mobileFile, m.cfg.Validation)
+	if err != nil {
+		return err
+	}
+	categories, err := loadOptionalCategories(m.fsys, m.cfg.Validation)
+	if err != nil {
+		return err
+	}
+	info, err := loadManifest(m.fsys)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name, agents := range m.registered {
+		categories[name] = agents
+	}
+	m.setDatasets(desktop, mobile, categories, nil)
+	m.info.Store(&info)
+	m.stamp = stamp
+	return nil
+}
+
+// Watch polls the Manager's dataset files every interval and calls Reload
+// when their size or modification time changes, until ctx is done. Reload
+// failures are passed to onError, which may be nil. Watch only detects
+// changes on file systems that report modification times, such as os.DirFS.
+// interval must be positive.
+func (m *Manager) Watch(ctx context.Context, interval time.Duration, onError func(error)) error {
+	if m.fsys == nil {
+		return ErrReloadUnsupported
+	}
+	if interval <= 0 {
+		return fmt.Errorf("watch interval must be positive, got %v", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	// failed remembers a version that did not load so it is reported once
+	// instead of on every poll.
+	var failed string
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		stamp, err := datasetStamp(m.fsys)
+		if err == nil {
+			m.mu.Lock()
+			unchanged := stamp == m.stamp
+			m.mu.Unlock()
+			if unchanged || stamp == failed {
+				continue
+			}
+			if err = m.Reload(); err != nil {
+				failed = stamp
+			}
+		}
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// datasetStamp summarises the size and modification time of every file
+// Reload reads from fsys, so Watch can tell when any of them has been
+// rewritten. Optional files that are missing are recorded as absent, so
+// adding one later also counts as a change.
+func datasetStamp(fsys fs.FS) (string, error) {
+	optional := make([]string, 0, len(optionalCategoryFiles))
+	for _, name := range optionalCategoryFiles {
+		optional = append(optional, name)
+	}
+	sort.Strings(optional)
+
+	var sb strings.Builder
+	stamp := func(required bool, candidates ...string) error {
+		var info fs.FileInfo
+		var err error
+		for _, candidate := range candidates {
+			if info, err = fs.Stat(fsys, candidate); !errors.Is(err, fs.ErrNotExist) {
+				break
+			}
+		}
+		if errors.Is(err, fs.ErrNotExist) && !required {
+			fmt.Fprintf(&sb, "%s:absent;", candidates[0])
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&sb, "%s:%d:%d;", info.Name(), info.Size(), info.ModTime().UnixNano())
+		return nil
+	}
+	for _, name := range []string{desktopFile, mobileFile} {
+		if err := stamp(true, datasetCandidates(name)...); err != nil {
+			return "", err
+		}
+	}
+	for _, name := range optional {
+		if err := stamp(false, datasetCandidates(name)...); err != nil {
+			return "", err
+		}
+	}
+	if err := stamp(false, manifestFile); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,117 @@
+package commonuseragent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeDatasets(t *testing.T, dir, desktopUA string) {
+	t.Helper()
+	files := map[string]string{
+		desktopFile: `[{"ua": "` + desktopUA + `", "pct": 100}]`,
+		mobileFile:  `[{"ua": "` + remoteMobileUA + `", "pct": 100}]`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	writeDatasets(t, dir, remoteDesktopUA)
+	m, err := NewManagerFromFS(os.DirFS(dir), DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromFS returned an error: %v", err)
+	}
+
+	updated := strings.Replace(remoteDesktopUA, "126.0", "127.0", 2)
+	writeDatasets(t, dir, updated)
+	if err := m.Reload(); err != nil {
+		t.Fatalf("Reload returned an error: %v", err)
+	}
+	if got := m.GetRandomDesktopUA(); got != updated {
+		t.Errorf("GetRandomDesktopUA returned %q after Reload", got)
+	}
+
+	writeDatasets(t, dir, "bad")
+	if err := m.Reload(); !errors.Is(err, ErrInvalidUserAgent) {
+		t.Errorf("Reload error = %v, want ErrInvalidUserAgent", err)
+	}
+	if got := m.GetRandomDesktopUA(); got != updated {
+		t.Errorf("failed Reload replaced the data with %q", got)
+	}
+}
+
+func TestReloadUnsupported(t *testing.T) {
+	m, err := NewManagerFromReaders(strings.NewReader(`[]`), strings.NewReader(`[]`), DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromReaders returned an error: %v", err)
+	}
+	if err := m.Reload(); !errors.Is(err, ErrReloadUnsupported) {
+		t.Errorf("Reload error = %v, want ErrReloadUnsupported", err)
+	}
+}
+
+func TestWatchRejectsInvalidInterval(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	if err := m.Watch(context.Background(), 0, nil); err == nil {
+		t.Errorf("Watch accepted a zero interval")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	writeDatasets(t, dir, remoteDesktopUA)
+	m, err := NewManagerFromFS(os.DirFS(dir), DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromFS returned an error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go m.Watch(ctx, 10*time.Millisecond, nil)
+
+	updated := strings.Replace(remoteDesktopUA, "126.0", "127.0", 2) + " Extra/1.0"
+	writeDatasets(t, dir, updated)
+	for m.GetRandomDesktopUA() != updated {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("Watch did not pick up the updated dataset")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestDatasetStampCoversAllFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeDatasets(t, dir, remoteDesktopUA)
+	fsys := os.DirFS(dir)
+	before, err := datasetStamp(fsys)
+	if err != nil {
+		t.Fatalf("datasetStamp returned an error: %v", err)
+	}
+
+	for _, name := range []string{"tv_useragents.json", manifestFile} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(`[]`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		after, err := datasetStamp(fsys)
+		if err != nil {
+			t.Fatalf("datasetStamp returned an error: %v", err)
+		}
+		if after == before {
+			t.Errorf("datasetStamp did not change when %s was written", name)
+		}
+		before = after
+	}
+}
@@ -0,0 +1,206 @@
+package commonuseragent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultRefreshInterval is how often a RemoteSource refreshes when no
+// interval is configured.
+const DefaultRefreshInterval = time.Hour
+
+// DefaultMaxRemoteSize is the largest dataset response a RemoteSource reads
+// when no limit is configured.
+const DefaultMaxRemoteSize = 32 << 20
+
+// RemoteConfig describes where a RemoteSource fetches its datasets from.
+type RemoteConfig struct {
+	// DesktopURL and MobileURL serve datasets in any schema version
+	// DecodeDataset understands.
+	DesktopURL string
+	MobileURL  string
+	// Interval between refreshes in Run. Defaults to DefaultRefreshInterval.
+	Interval time.Duration
+	// Client is used for requests. Defaults to http.DefaultClient.
+	Client *http.Client
+	// MaxSize is the largest response body, in bytes, accepted for either
+	// dataset. Larger responses fail the refresh. Defaults to
+	// DefaultMaxRemoteSize.
+	MaxSize int64
+	// OnError, if set, is called with every failed refresh.
+	OnError func(error)
+}
+
+// remoteDataset caches the last good response for one URL so unchanged
+// data can be revalidated with a conditional request.
+type remoteDataset struct {
+	etag         string
+	lastModified string
+	agents       []UserAgent
+}
+
+// RemoteSource keeps a Manager's datasets in sync with remote URLs. Each
+// refresh validates the downloaded data, rejecting empty or oversized
+// datasets, and swaps both lists into the
+// Manager at once; if a refresh fails the Manager keeps its current data,
+// falling back to the embedded datasets only if it holds no agents at all.
+type RemoteSource struct {
+	m   *Manager
+	cfg RemoteConfig
+
+	mu       sync.Mutex
+	desktop  remoteDataset
+	mobile   remoteDataset
+	loaded   bool
+	fellBack bool
+	// pending is set when one dataset changed but could not be swapped in
+	// because the other failed to download.
+	pending   bool
+	lastError error
+	lastFail  time.Time
+}
+
+// NewRemoteSource returns a RemoteSource that refreshes m.
+func NewRemoteSource(m *Manager, cfg RemoteConfig) *RemoteSource {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultRefreshInterval
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = DefaultMaxRemoteSize
+	}
+	return &RemoteSource{m: m, cfg: cfg}
+}
+
+// Run refreshes immediately and then every Interval until ctx is done.
+// Errors are reported through OnError and LastError.
+func (s *RemoteSource) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		s.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh fetches both datasets once and swaps them into the Manager if
+// either changed.
+func (s *RemoteSource) Refresh(ctx context.Context) error {
+	if s.m.readOnly {
+		return ErrReadOnly
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	desktopChanged, err := s.fetch(ctx, "desktop", s.cfg.DesktopURL, &s.desktop)
+	s.pending = s.pending || desktopChanged
+	if err != nil {
+		return s.fail(err)
+	}
+	mobileChanged, err := s.fetch(ctx, "mobile", s.cfg.MobileURL, &s.mobile)
+	s.pending = s.pending || mobileChanged
+	if err != nil {
+		return s.fail(err)
+	}
+
+	if s.pending || !s.loaded {
+		s.m.swap(
+			append([]UserAgent(nil), s.desktop.agents...),
+			append([]UserAgent(nil), s.mobile.agents...),
+		)
+	}
+	s.pending = false
+	s.loaded = true
+	s.lastError = nil
+	return nil
+}
+
+// LastError returns when the most recent failed refresh happened and its
+// error. The error is nil if the latest refresh succeeded.
+func (s *RemoteSource) LastError() (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastFail, s.lastError
+}
+
+// fetch downloads url into ds, reporting whether the data changed. A 304
+// response keeps the cached agents.
+func (s *RemoteSource) fetch(ctx context.Context, name, url string, ds *remoteDataset) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", name, err)
+	}
+	if ds.agents != nil {
+		if ds.etag != "" {
+			req.Header.Set("If-None-Match", ds.etag)
+		}
+		if ds.lastModified != "" {
+			req.Header.Set("If-Modified-Since", ds.lastModified)
+		}
+	}
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ds.agents != nil:
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("%s: fetching %s: unexpected status %s", name, url, resp.Status)
+	}
+
+	body := &io.LimitedReader{R: resp.Body, N: s.cfg.MaxSize + 1}
+	agents, err := readCategory(name, body, s.m.cfg.Validation)
+	if body.N <= 0 {
+		return false, fmt.Errorf("%s: fetching %s: response exceeds %d bytes", name, url, s.cfg.MaxSize)
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(agents) == 0 {
+		// An empty list would leave the Manager with nothing to serve.
+		return false, fmt.Errorf("%s: fetching %s: dataset is empty", name, url)
+	}
+	*ds = remoteDataset{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		agents:       agents,
+	}
+	return true, nil
+}
+
+// fail records err and, if nothing has been fetched yet and the Manager
+// holds no agents of its own, falls back to the embedded datasets once.
+func (s *RemoteSource) fail(err error) error {
+	s.lastError = err
+	s.lastFail = time.Now()
+	if s.cfg.OnError != nil {
+		s.cfg.OnError(err)
+	}
+	if s.loaded || s.fellBack {
+		return err
+	}
+	if cur := s.m.snap.Load(); len(cur.desktop)+len(cur.mobile) > 0 {
+		return err
+	}
+	desktop, derr := loadCategory(content, desktopFile, s.m.cfg.Validation)
+	mobile, merr := loadCategory(content, mobileFile, s.m.cfg.Validation)
+	if derr == nil && merr == nil {
+		s.m.swap(desktop, mobile)
+		s.fellBack = true
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+package commonuseragent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+)
+
+const (
+	remoteDesktopUA = "Mozilla/5.0 (X11; Linux x86_64; rv:126.0) Gecko/20100101 Firefox/126.0"
+	remoteMobileUA  = "Mozilla/5.0 (Android 14; Mobile; rv:126.0) Gecko/126.0 Firefox/126.0"
+)
+
+func newRemoteTestServer(t *testing.T, notModified *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		switch r.URL.Path {
+		case "/desktop":
+			w.Write([]byte(`[{"ua": "` + remoteDesktopUA + `", "pct": 100}]`))
+		case "/mobile":
+			w.Write([]byte(`[{"ua": "` + remoteMobileUA + `", "pct": 100}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRemoteSourceRefresh(t *testing.T) {
+	var notModified atomic.Int32
+	srv := newRemoteTestServer(t, &notModified)
+
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	src := NewRemoteSource(m, RemoteConfig{DesktopURL: srv.URL + "/desktop", MobileURL: srv.URL + "/mobile"})
+
+	if err := src.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh returned an error: %v", err)
+	}
+	if got := m.GetRandomDesktopUA(); got != remoteDesktopUA {
+		t.Errorf("GetRandomDesktopUA returned %q after refresh", got)
+	}
+	if got := m.GetRandomMobileUA(); got != remoteMobileUA {
+		t.Errorf("GetRandomMobileUA returned %q after refresh", got)
+	}
+
+	if err := src.Refresh(context.Background()); err != nil {
+		t.Fatalf("second Refresh returned an error: %v", err)
+	}
+	if notModified.Load() != 2 {
+		t.Errorf("second Refresh made %d conditional hits, want 2", notModified.Load())
+	}
+}
+
+func TestRemoteSourceFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"ua": "bad", "pct": 1}]`))
+	}))
+	defer srv.Close()
+
+	fsys := fstest.MapFS{
+		"desktop_useragents.json": &fstest.MapFile{Data: []byte(`[{"ua": "` + remoteDesktopUA + `", "pct": 100}]`)},
+		"mobile_useragents.json":  &fstest.MapFile{Data: []byte(`[{"ua": "` + remoteMobileUA + `", "pct": 100}]`)},
+	}
+	m, err := NewManagerFromFS(fsys, DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromFS returned an error: %v", err)
+	}
+
+	var reported error
+	src := NewRemoteSource(m, RemoteConfig{
+		DesktopURL: srv.URL,
+		MobileURL:  srv.URL,
+		OnError:    func(err error) { reported = err },
+	})
+	if err := src.Refresh(context.Background()); err == nil {
+		t.Fatalf("Refresh accepted an invalid dataset")
+	}
+	if reported == nil {
+		t.Errorf("OnError was not called")
+	}
+	if at, err := src.LastError(); err == nil || at.IsZero() {
+		t.Errorf("LastError() = %v, %v", at, err)
+	}
+	if got := m.GetAllDesktop(); len(got) != 1 || got[0].UA != remoteDesktopUA {
+		t.Errorf("a failed refresh replaced the Manager's own data: %d agents", len(got))
+	}
+
+	empty, _ := newManager(DefaultConfig(), nil, nil, nil)
+	src = NewRemoteSource(empty, RemoteConfig{DesktopURL: srv.URL, MobileURL: srv.URL})
+	src.Refresh(context.Background())
+	if got := empty.GetAllDesktop(); len(got) != len(Default().GetAllDesktop()) {
+		t.Errorf("an empty Manager did not fall back to the embedded datasets")
+	}
+}
+
+func TestRemoteSourceRejectsEmptyAndOversized(t *testing.T) {
+	for _, body := range []string{`[]`, `{"version": 2, "agents": []}`, `[{"ua": "` + remoteDesktopUA + `", "pct": 100}, {"ua": "` + remoteMobileUA + `", "pct": 100}]`} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}))
+		m, err := NewManager()
+		if err != nil {
+			t.Fatalf("NewManager returned an error: %v", err)
+		}
+		want := len(m.GetAllDesktop())
+		src := NewRemoteSource(m, RemoteConfig{DesktopURL: srv.URL, MobileURL: srv.URL, MaxSize: 100})
+		if err := src.Refresh(context.Background()); err == nil {
+			t.Errorf("Refresh accepted %q", body)
+		}
+		if got := len(m.GetAllDesktop()); got != want || m.GetRandomUA() == "" {
+			t.Errorf("Refresh with %q replaced the Manager's data: %d agents, want %d", body, got, want)
+		}
+		srv.Close()
+	}
+}
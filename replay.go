@@ -0,0 +1,108 @@
+package commonuseragent
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Selection strategies recorded in replay logs.
+const (
+	strategyUniform  = "uniform"
+	strategyWeighted = "weighted"
+	strategyFresh    = "fresh"
+	strategyEngine   = "engine"
+	strategyPair     = "pair"
+	strategyRecipe   = "recipe"
+	strategySession  = "session"
+	strategyQuery    = "query"
+	strategyLocale   = "locale"
+)
+
+// WithReplayLog makes the Manager append every agent it serves to w as one
+// tab-separated line of timestamp, strategy and UA, so an exact sequence can
+// be reproduced later with a Replayer. Write errors are ignored so logging
+// can never break selection.
+func WithReplayLog(w io.Writer) Option {
+	return func(c *Config) {
+		c.ReplayLog = w
+	}
+}
+
+// served records agent in the replay log and reports it to the observer,
+// if either is configured, and returns it unchanged. start is when the
+// selection began, from m.start; passed as the first argument it is
+// evaluated before a selection made in a later argument. category names the
+// dataset agent was picked from, or is "" for a pick spanning several, in
+// which case it is looked up only if an observer needs it. Empty results are
+// not recorded.
+func (m *Manager) served(start time.Time, strategy, category string, agent UserAgent) UserAgent {
+	if agent.UA == "" {
+		return agent
+	}
+	if m.cfg.Observer != nil {
+		if category == "" {
+			category = m.snap.Load().categoryOf(agent.UA)
+		}
+		m.cfg.Observer(SelectionEvent{
+			Agent:    agent,
+			Category: category,
+			Device:   agent.Device,
+			Strategy: strategy,
+			Latency:  time.Since(start),
+		})
+	}
+	if m.cfg.ReplayLog != nil {
+		m.replayMu.Lock()
+		defer m.replayMu.Unlock()
+		fmt.Fprintf(m.cfg.ReplayLog, "%s\t%s\t%s\n", time.Now().UTC().Format(time.RFC3339Nano), strategy, agent.UA)
+	}
+	return agent
+}
+
+// ReplayEntry is one served agent read back from a replay log.
+type ReplayEntry struct {
+	Time     time.Time
+	Strategy string
+	UA       string
+}
+
+// Replayer reads a replay log written through WithReplayLog and re-emits the
+// recorded agents in their original order.
+type Replayer struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewReplayer returns a Replayer reading from r.
+func NewReplayer(r io.Reader) *Replayer {
+	return &Replayer{scanner: bufio.NewScanner(r)}
+}
+
+// Next returns the next recorded entry, or io.EOF when the log is exhausted.
+func (r *Replayer) Next() (ReplayEntry, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return ReplayEntry{}, err
+		}
+		return ReplayEntry{}, io.EOF
+	}
+	r.line++
+	fields := strings.SplitN(r.scanner.Text(), "\t", 3)
+	if len(fields) != 3 {
+		return ReplayEntry{}, fmt.Errorf("replay line %d: expected 3 tab-separated fields", r.line)
+	}
+	t, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return ReplayEntry{}, fmt.Errorf("replay line %d: %w", r.line, err)
+	}
+	return ReplayEntry{Time: t, Strategy: fields[1], UA: fields[2]}, nil
+}
+
+// NextUA returns just the UA string of the next recorded entry.
+func (r *Replayer) NextUA() (string, error) {
+	e, err := r.Next()
+	return e.UA, err
+}
@@ -0,0 +1,44 @@
+package commonuseragent
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReplayLogRoundTrip(t *testing.T) {
+	var log bytes.Buffer
+	m, err := NewManager(WithReplayLog(&log))
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	served := []string{
+		m.GetRandomDesktopUA(),
+		m.GetWeightedRandomUA(),
+		m.GetFreshRandomMobile().UA,
+	}
+
+	r := NewReplayer(&log)
+	strategies := []string{strategyUniform, strategyWeighted, strategyFresh}
+	for i, want := range served {
+		e, err := r.Next()
+		if err != nil {
+			t.Fatalf("Next returned an error: %v", err)
+		}
+		if e.UA != want || e.Strategy != strategies[i] || e.Time.IsZero() {
+			t.Errorf("entry %d = %+v, want UA %q via %s", i, e, want, strategies[i])
+		}
+	}
+	if _, err := r.NextUA(); !errors.Is(err, io.EOF) {
+		t.Errorf("NextUA at end of log returned %v, want io.EOF", err)
+	}
+}
+
+func TestReplayerMalformed(t *testing.T) {
+	r := NewReplayer(strings.NewReader("not a replay line\n"))
+	if _, err := r.Next(); err == nil || errors.Is(err, io.EOF) {
+		t.Errorf("Next accepted a malformed line: %v", err)
+	}
+}
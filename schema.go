@@ -0,0 +1,183 @@
+package commonuseragent
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// Dataset schema versions. Version 1 is a bare JSON array of {"ua", "pct"}
+// objects; version 2 wraps the agents in a versioned document and adds the
+// browser, browser_version, os and device fields.
+const (
+	SchemaV1 = 1
+	SchemaV2 = 2
+
+	CurrentSchemaVersion = SchemaV2
+)
+
+// Dataset is the version 2 on-disk form of a list of user agents.
+type Dataset struct {
+	Version int         `json:"version"`
+	Agents  []UserAgent `json:"agents"`
+}
+
+// DecodeDataset reads a dataset in any supported schema version and returns
+// its agents migrated to the current version: metadata missing from older
+// files is filled in by parsing each UA string.
+func DecodeDataset(r io.Reader) ([]UserAgent, error) {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(br)
+	var agents []UserAgent
+	switch first {
+	case '[':
+		if agents, err = decodeAgentArray(dec); err != nil {
+			return nil, err
+		}
+	case '{':
+		if agents, err = decodeVersionedDataset(dec); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unrecognized dataset format starting with %q", first)
+	}
+
+	migrateAgents(agents)
+	return agents, nil
+}
+
+// decodeAgentArray decodes a JSON array of agents one element at a time.
+// Decoding the array as a single value would make the decoder buffer the
+// whole input first, doubling peak memory for very large datasets.
+func decodeAgentArray(dec *json.Decoder) ([]UserAgent, error) {
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	agents := []UserAgent{}
+	interned := make(metadataInterner)
+	for dec.More() {
+		var agent UserAgent
+		if err := dec.Decode(&agent); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", len(agents), err)
+		}
+		interned.intern(&agent)
+		agents = append(agents, agent)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return agents, nil
+}
+
+// metadataInterner shares one copy of each metadata string across the agents
+// of a dataset. The values repeat across almost every entry, so this avoids
+// keeping a separate string per agent.
+type metadataInterner map[string]string
+
+// intern replaces the metadata strings of agent with their shared copies.
+func (in metadataInterner) intern(agent *UserAgent) {
+	agent.Browser = in.string(agent.Browser)
+	agent.BrowserVersion = in.string(agent.BrowserVersion)
+	agent.OS = in.string(agent.OS)
+}
+
+func (in metadataInterner) string(s string) string {
+	if v, ok := in[s]; ok {
+		return v
+	}
+	in[s] = s
+	return s
+}
+
+// decodeVersionedDataset streams a Dataset document, decoding its agents
+// array element by element. The version may appear before or after the
+// agents. A document without an agents key is an error, so a truncated or
+// mis-keyed file is not mistaken for an empty dataset.
+func decodeVersionedDataset(dec *json.Decoder) ([]UserAgent, error) {
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	version := 0
+	var agents []UserAgent
+	hasAgents := false
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch tok {
+		case "version":
+			if err := dec.Decode(&version); err != nil {
+				return nil, fmt.Errorf("version: %w", err)
+			}
+		case "agents":
+			hasAgents = true
+			if agents, err = decodeAgentArray(dec); err != nil {
+				return nil, fmt.Errorf("agents: %w", err)
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if version < SchemaV1 || version > CurrentSchemaVersion {
+		return nil, fmt.Errorf("unsupported dataset schema version %d", version)
+	}
+	if !hasAgents {
+		return nil, fmt.Errorf("dataset has no agents key")
+	}
+	return agents, nil
+}
+
+// peekNonSpace skips leading whitespace and returns the next byte without
+// consuming it.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if !unicode.IsSpace(rune(b)) {
+			return b, br.UnreadByte()
+		}
+	}
+}
+
+// migrateAgents fills in the version 2 metadata fields that are empty,
+// leaving values supplied by the dataset untouched.
+func migrateAgents(agents []UserAgent) {
+	for i := range agents {
+		a := &agents[i]
+		if a.Browser != "" && a.BrowserVersion != "" && a.OS != "" && a.Device != "" {
+			continue
+		}
+		p, err := Parse(a.UA)
+		if err != nil {
+			continue
+		}
+		if a.Browser == "" {
+			a.Browser = p.Browser
+		}
+		if a.BrowserVersion == "" {
+			a.BrowserVersion = p.BrowserVersion
+		}
+		if a.OS == "" {
+			a.OS = p.OS
+		}
+		if a.Device == "" {
+			a.Device = p.Device
+		}
+	}
+}
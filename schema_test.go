@@ -0,0 +1,106 @@
+package commonuseragent
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+const testFirefoxUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0"
+
+func TestDecodeDatasetV1(t *testing.T) {
+	agents, err := DecodeDataset(strings.NewReader(` [{"ua": "` + testFirefoxUA + `", "pct": 3.93}]`))
+	if err != nil {
+		t.Fatalf("DecodeDataset returned an error: %v", err)
+	}
+	want := UserAgent{UA: testFirefoxUA, Pct: 3.93, Browser: "Firefox", BrowserVersion: "125.0", OS: "Windows", Device: DeviceDesktop}
+	if len(agents) != 1 || agents[0] != want {
+		t.Errorf("DecodeDataset migrated v1 to %+v, want %+v", agents, want)
+	}
+}
+
+func TestDecodeDatasetV2(t *testing.T) {
+	input := `{"version": 2, "agents": [{"ua": "` + testFirefoxUA + `", "pct": 1, "browser": "Custom Fox", "device": "tablet"}]}`
+	agents, err := DecodeDataset(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("DecodeDataset returned an error: %v", err)
+	}
+	if len(agents) != 1 || agents[0].Browser != "Custom Fox" || agents[0].Device != DeviceTablet || agents[0].OS != "Windows" {
+		t.Errorf("DecodeDataset returned %+v", agents)
+	}
+}
+
+func TestDecodeDatasetUnsupported(t *testing.T) {
+	for _, input := range []string{`{"version": 9, "agents": []}`, `"agents"`, ``} {
+		if _, err := DecodeDataset(strings.NewReader(input)); err == nil {
+			t.Errorf("DecodeDataset(%q) did not return an error", input)
+		}
+	}
+}
+
+func TestDecodeDatasetMissingAgents(t *testing.T) {
+	for _, input := range []string{`{"version": 2}`, `{"version": 2, "agent": [{"ua": "` + testFirefoxUA + `", "pct": 1}]}`} {
+		if _, err := DecodeDataset(strings.NewReader(input)); err == nil {
+			t.Errorf("DecodeDataset(%q) did not return an error", input)
+		}
+	}
+	if agents, err := DecodeDataset(strings.NewReader(`{"version": 2, "agents": []}`)); err != nil || len(agents) != 0 {
+		t.Errorf("DecodeDataset rejected an explicitly empty agents array: %v", err)
+	}
+}
+
+func TestDecodeDatasetVersionAfterAgents(t *testing.T) {
+	input := `{"agents": [{"ua": "` + testFirefoxUA + `", "pct": 1}], "source": "test", "version": 2}`
+	agents, err := DecodeDataset(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("DecodeDataset returned an error: %v", err)
+	}
+	if len(agents) != 1 {
+		t.Errorf("DecodeDataset returned %d agents, want 1", len(agents))
+	}
+}
+
+func BenchmarkDecodeDatasetLarge(b *testing.B) {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := 0; i < 100000; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(`{"ua": "` + testFirefoxUA + `", "pct": 0.001, "browser": "Firefox", "browser_version": "125.0", "os": "Windows", "device": "desktop"}`)
+	}
+	sb.WriteString("]")
+	input := sb.String()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeDataset(strings.NewReader(input)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEmbeddedDatasetsCarryMetadata(t *testing.T) {
+	for _, name := range []string{desktopFile, mobileFile} {
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc struct {
+			Version int         `json:"version"`
+			Agents  []UserAgent `json:"agents"`
+		}
+		err = json.NewDecoder(f).Decode(&doc)
+		f.Close()
+		if err != nil || doc.Version != SchemaV2 {
+			t.Fatalf("%s is not a version 2 dataset: %v", name, err)
+		}
+		for _, agent := range doc.Agents {
+			if agent.OS == "" || agent.Device == "" {
+				t.Errorf("%s: %q has no stored metadata", name, agent.UA)
+			}
+		}
+	}
+}
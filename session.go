@@ -0,0 +1,109 @@
+package commonuseragent
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"time"
+)
+
+// WithSessionTTL makes GetSessionUA give each session a new agent once ttl
+// has passed since the session's agent was assigned. Zero, the default,
+// keeps a session's agent forever.
+func WithSessionTTL(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.SessionTTL = ttl
+	}
+}
+
+// GetSessionUA returns the UA string assigned to sessionID, so a scraping
+// session or worker keeps one consistent identity across requests.
+//
+// Without a TTL the assignment is derived from the ID rather than stored: it
+// is the same on every call, in every process holding the same data, and
+// survives restarts. It uses rendezvous hashing, so deprecating or removing
+// an agent only moves the sessions that were using it. With WithSessionTTL
+// the Manager remembers when each session was assigned its agent and moves
+// it to a new one on the first call after TTL has passed. Sessions idle for
+// longer than the TTL are forgotten, so the memory used stays proportional
+// to the number of active sessions.
+func (m *Manager) GetSessionUA(sessionID string) string {
+	return m.sessionUA(sessionID, time.Now())
+}
+
+// sessionUA returns the agent for sessionID at time now.
+func (m *Manager) sessionUA(sessionID string, now time.Time) string {
+	start := m.start()
+	generation := m.sessionGeneration(sessionID, now)
+	s := m.snap.Load()
+
+	var best UserAgent
+	var bestScore uint64
+	for _, list := range [][]UserAgent{s.desktop, s.mobile} {
+		for _, agent := range list {
+			if agent.Deprecated {
+				continue
+			}
+			if score := sessionHash(sessionID, generation, agent.UA); best.UA == "" || score > bestScore {
+				best, bestScore = agent, score
+			}
+		}
+	}
+	return m.served(start, strategySession, "", best).UA
+}
+
+// sessionState tracks the agent period of one session under a TTL.
+type sessionState struct {
+	generation uint64
+	expires    time.Time
+}
+
+// sessionGeneration returns the generation of sessionID's current agent,
+// starting a new one when the session is new or its TTL has run out.
+func (m *Manager) sessionGeneration(sessionID string, now time.Time) uint64 {
+	ttl := m.cfg.SessionTTL
+	if ttl <= 0 {
+		return 0
+	}
+	m.sessionMu.Lock()
+	defer m.sessionMu.Unlock()
+
+	// Forget expired sessions once per TTL; they would get a new agent on
+	// their next call anyway.
+	if !now.Before(m.sessionSweep) {
+		for id, st := range m.sessions {
+			if !now.Before(st.expires) {
+				delete(m.sessions, id)
+			}
+		}
+		m.sessionSweep = now.Add(ttl)
+	}
+
+	st, ok := m.sessions[sessionID]
+	switch {
+	case !ok:
+		// Start from the current TTL period so a forgotten session does
+		// not come back to an agent it used before.
+		st.generation = uint64(now.UnixNano()) / uint64(ttl)
+	case !now.Before(st.expires):
+		st.generation++
+	default:
+		return st.generation
+	}
+	st.expires = now.Add(ttl)
+	if m.sessions == nil {
+		m.sessions = make(map[string]sessionState)
+	}
+	m.sessions[sessionID] = st
+	return st.generation
+}
+
+// sessionHash scores ua for a session generation.
+func sessionHash(sessionID string, generation uint64, ua string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(sessionID))
+	var gen [9]byte
+	binary.BigEndian.PutUint64(gen[1:], generation)
+	h.Write(gen[:])
+	h.Write([]byte(ua))
+	return h.Sum64()
+}
@@ -0,0 +1,88 @@
+package commonuseragent
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestGetSessionUA(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	other, _ := NewManager()
+
+	ua := m.GetSessionUA("worker-1")
+	if ua == "" {
+		t.Fatalf("GetSessionUA returned an empty user agent")
+	}
+	for i := 0; i < 10; i++ {
+		if got := m.GetSessionUA("worker-1"); got != ua {
+			t.Fatalf("GetSessionUA changed from %q to %q", ua, got)
+		}
+	}
+	if got := other.GetSessionUA("worker-1"); got != ua {
+		t.Errorf("GetSessionUA differs between Managers: %q vs %q", ua, got)
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		seen[m.GetSessionUA(id)] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("GetSessionUA mapped every session to the same agent")
+	}
+
+	m.Deprecate(ua)
+	if got := m.GetSessionUA("worker-1"); got == ua {
+		t.Errorf("GetSessionUA returned a deprecated agent")
+	}
+}
+
+func TestGetSessionUATTL(t *testing.T) {
+	m, err := NewManager(WithSessionTTL(time.Hour))
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	changes := 0
+	prev := m.sessionUA("worker-1", start)
+	for minute := 1; minute <= 20*60; minute++ {
+		got := m.sessionUA("worker-1", start.Add(time.Duration(minute)*time.Minute))
+		if got != prev {
+			changes++
+		}
+		prev = got
+	}
+	if changes == 0 || changes > 20 {
+		t.Errorf("session agent changed %d times over 20 TTLs, want between 1 and 20", changes)
+	}
+}
+
+func TestGetSessionUATTLFromFirstUse(t *testing.T) {
+	m, err := NewManager(WithSessionTTL(time.Hour))
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for minute := 0; minute < 60; minute++ {
+		id := fmt.Sprintf("worker-%d", minute)
+		first := start.Add(time.Duration(minute) * time.Minute)
+		ua := m.sessionUA(id, first)
+		if got := m.sessionUA(id, first.Add(time.Hour-time.Second)); got != ua {
+			t.Fatalf("session first seen at %v changed agent before its TTL ran out", first)
+		}
+	}
+
+	// Sessions idle for longer than the TTL are forgotten.
+	m.sessionUA("late", start.Add(3*time.Hour))
+	m.sessionMu.Lock()
+	n := len(m.sessions)
+	m.sessionMu.Unlock()
+	if n != 1 {
+		t.Errorf("Manager still tracks %d sessions, want 1", n)
+	}
+}
@@ -0,0 +1,147 @@
+package commonuseragent
+
+import (
+	"sync"
+)
+
+// snapshot is an immutable view of a Manager's datasets and everything
+// derived from them. Once published it is never modified, so readers can use
+// it without locking; any change builds a new snapshot.
+type snapshot struct {
+	desktop []UserAgent
+	mobile  []UserAgent
+	// categories holds every other dataset, such as tv and console.
+	categories map[string][]UserAgent
+	// latest maps each browser family to its newest major version.
+	latest   map[string]int
+	warnings []LoadWarning
+	// activeDesktop, activeMobile and activeCategories hold each dataset
+	// without its deprecated agents, so uniform and weighted picks stay
+	// constant-time or logarithmic however many agents are deprecated. A
+	// dataset with nothing deprecated shares its backing array.
+	activeDesktop    []UserAgent
+	activeMobile     []UserAgent
+	activeCategories map[string][]UserAgent
+
+	desktopWeights cumulativeWeights
+	mobileWeights  cumulativeWeights
+	allWeights     cumulativeWeights
+	// freshDesktopWeights and freshMobileWeights scale each Pct by the
+	// agent's freshness score, for the GetFresh* selectors.
+	freshDesktopWeights cumulativeWeights
+	freshMobileWeights  cumulativeWeights
+	pairs               pairIndex
+	// origin maps each UA to the first dataset holding it. It is built on
+	// first use, since only observers need it.
+	origin func() map[string]string
+}
+
+// newSnapshot builds a snapshot over the given datasets, which the caller
+// must not modify afterwards, scoring freshness with freshness.
+func newSnapshot(desktop, mobile []UserAgent, categories map[string][]UserAgent, warnings []LoadWarning, freshness FreshnessConfig) *snapshot {
+	s := &snapshot{
+		desktop:    desktop,
+		mobile:     mobile,
+		categories: categories,
+		warnings:   warnings,
+	}
+	s.latest = latestVersions(s.lists()...)
+	s.activeDesktop = withoutDeprecated(desktop)
+	s.activeMobile = withoutDeprecated(mobile)
+	s.activeCategories = make(map[string][]UserAgent, len(categories))
+	for name, list := range categories {
+		s.activeCategories[name] = withoutDeprecated(list)
+	}
+	s.desktopWeights = newCumulativeWeights(s.activeDesktop)
+	s.mobileWeights = newCumulativeWeights(s.activeMobile)
+	s.allWeights = newCumulativeWeights(s.activeDesktop, s.activeMobile)
+	s.freshDesktopWeights = newFreshWeights(freshness, s.latest, s.activeDesktop)
+	s.freshMobileWeights = newFreshWeights(freshness, s.latest, s.activeMobile)
+	s.pairs = newPairIndex(desktop, mobile)
+	s.origin = sync.OnceValue(s.buildOrigin)
+	return s
+}
+
+// buildOrigin maps every UA in s to the first dataset holding it.
+func (s *snapshot) buildOrigin() map[string]string {
+	origin := make(map[string]string)
+	for _, name := range append([]string{"desktop", "mobile"}, sortedCategoryNames(s.categories)...) {
+		list, _ := s.category(name)
+		for _, agent := range list {
+			if _, ok := origin[agent.UA]; !ok {
+				origin[agent.UA] = name
+			}
+		}
+	}
+	return origin
+}
+
+// categoryOf returns the name of the first dataset holding ua, or "" if no
+// dataset does.
+func (s *snapshot) categoryOf(ua string) string {
+	return s.origin()[ua]
+}
+
+// lists returns every dataset in the snapshot: desktop, mobile, then the
+// other categories in name order.
+func (s *snapshot) lists() [][]UserAgent {
+	all := [][]UserAgent{s.desktop, s.mobile}
+	for _, name := range sortedCategoryNames(s.categories) {
+		all = append(all, s.categories[name])
+	}
+	return all
+}
+
+// activeCategory is like category but leaves out deprecated agents.
+func (s *snapshot) activeCategory(name string) ([]UserAgent, bool) {
+	switch name {
+	case "desktop":
+		return s.activeDesktop, true
+	case "mobile":
+		return s.activeMobile, true
+	}
+	list, ok := s.activeCategories[name]
+	return list, ok
+}
+
+// withoutDeprecated returns agents without its deprecated entries, or agents
+// itself if none is deprecated.
+func withoutDeprecated(agents []UserAgent) []UserAgent {
+	n := countDeprecated(agents)
+	if n == 0 {
+		return agents
+	}
+	active := make([]UserAgent, 0, len(agents)-n)
+	for _, agent := range agents {
+		if !agent.Deprecated {
+			active = append(active, agent)
+		}
+	}
+	return active
+}
+
+// pick returns a uniformly chosen element of lists, treated as one list, or
+// the zero UserAgent if there is none. Callers pass lists that hold no
+// deprecated agents, such as the snapshot's active lists or a Query's
+// matches. It never allocates, so callers can pass several datasets instead
+// of concatenating them.
+func (s *snapshot) pick(rnd randSource, lists ...[]UserAgent) UserAgent {
+	total := 0
+	for _, list := range lists {
+		total += len(list)
+	}
+	if total == 0 {
+		return UserAgent{}
+	}
+	k, ok := rnd.intn(total)
+	if !ok {
+		return UserAgent{}
+	}
+	for _, list := range lists {
+		if k < len(list) {
+			return list[k]
+		}
+		k -= len(list)
+	}
+	return UserAgent{}
+}
@@ -0,0 +1,40 @@
+package commonuseragent
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSnapshotConcurrentWrites(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	target := m.GetAllDesktop()[0].UA
+	before := m.snap.Load()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Deprecate(target)
+				m.Undeprecate(target)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if m.GetRandomUA() == "" {
+					t.Error("GetRandomUA returned an empty user agent")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if before.desktop[0].Deprecated {
+		t.Errorf("Deprecate modified a published snapshot")
+	}
+}
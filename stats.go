@@ -0,0 +1,90 @@
+package commonuseragent
+
+import (
+	"sort"
+	"time"
+)
+
+// DatasetStats summarises the agents a Manager holds.
+type DatasetStats struct {
+	// Agents counts the agents in each dataset, keyed by category name.
+	Agents map[string]int
+	// Deprecated counts deprecated agents across all datasets.
+	Deprecated int
+	// The remaining counts and the Pct summary cover desktop and mobile
+	// agents. Agents whose browser or OS is not known are counted under "".
+	ByBrowser map[string]int
+	ByOS      map[string]int
+	ByDevice  map[DeviceClass]int
+	Pct       PctStats
+	// Collected is when the data was gathered, from DatasetInfo, and Age is
+	// how long ago that was. Both are zero if the collection date is unknown.
+	Collected time.Time
+	Age       time.Duration
+}
+
+// PctStats describes the distribution of Pct values.
+type PctStats struct {
+	Sum    float64
+	Min    float64
+	Max    float64
+	Median float64
+}
+
+// Stats returns a summary of the Manager's datasets: how many agents each
+// holds, the browser, OS and device mix, the spread of usage shares and how
+// old the data is.
+func (m *Manager) Stats() DatasetStats {
+	s := m.snap.Load()
+	st := DatasetStats{
+		Agents:    make(map[string]int),
+		ByBrowser: make(map[string]int),
+		ByOS:      make(map[string]int),
+		ByDevice:  make(map[DeviceClass]int),
+	}
+	for _, name := range append([]string{"desktop", "mobile"}, sortedCategoryNames(s.categories)...) {
+		list, _ := s.category(name)
+		st.Agents[name] = len(list)
+		st.Deprecated += countDeprecated(list)
+	}
+
+	var pcts []float64
+	for _, list := range [][]UserAgent{s.desktop, s.mobile} {
+		for _, agent := range list {
+			st.ByBrowser[agent.Browser]++
+			st.ByOS[agent.OS]++
+			st.ByDevice[agent.Device]++
+			pcts = append(pcts, agent.Pct)
+		}
+	}
+	st.Pct = pctStats(pcts)
+
+	if info := m.DatasetInfo(); !info.Collected.IsZero() {
+		st.Collected = info.Collected
+		st.Age = time.Since(info.Collected)
+	}
+	return st
+}
+
+// pctStats summarises pcts, which it sorts in place.
+func pctStats(pcts []float64) PctStats {
+	if len(pcts) == 0 {
+		return PctStats{}
+	}
+	sort.Float64s(pcts)
+	st := PctStats{Min: pcts[0], Max: pcts[len(pcts)-1]}
+	for _, p := range pcts {
+		st.Sum += p
+	}
+	if mid := len(pcts) / 2; len(pcts)%2 == 1 {
+		st.Median = pcts[mid]
+	} else {
+		st.Median = (pcts[mid-1] + pcts[mid]) / 2
+	}
+	return st
+}
+
+// GetStats returns a summary of the embedded datasets.
+func GetStats() DatasetStats {
+	return Default().Stats()
+}
@@ -0,0 +1,37 @@
+package commonuseragent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	desktop := strings.NewReader(`[
+		{"ua": "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", "pct": 10},
+		{"ua": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", "pct": 50},
+		{"ua": "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0", "pct": 30, "deprecated": true}
+	]`)
+	mobile := strings.NewReader(`[{"ua": "Mozilla/5.0 (Android 14; Mobile; rv:125.0) Gecko/125.0 Firefox/125.0", "pct": 20}]`)
+	m, err := NewManagerFromReaders(desktop, mobile, DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewManagerFromReaders returned an error: %v", err)
+	}
+
+	st := m.Stats()
+	if st.Agents["desktop"] != 3 || st.Agents["mobile"] != 1 || st.Deprecated != 1 {
+		t.Errorf("counts = %v, deprecated %d", st.Agents, st.Deprecated)
+	}
+	if st.ByBrowser["Firefox"] != 3 || st.ByBrowser["Chrome"] != 1 || st.ByOS["Windows"] != 2 || st.ByDevice[DeviceMobile] != 1 {
+		t.Errorf("mix = %v %v %v", st.ByBrowser, st.ByOS, st.ByDevice)
+	}
+	if want := (PctStats{Sum: 110, Min: 10, Max: 50, Median: 25}); st.Pct != want {
+		t.Errorf("Pct = %+v, want %+v", st.Pct, want)
+	}
+	if !st.Collected.IsZero() || st.Age != 0 {
+		t.Errorf("dataset without a manifest reported age %v", st.Age)
+	}
+
+	if embedded := GetStats(); embedded.Age != 0 || embedded.Agents[CategoryTV] == 0 {
+		t.Errorf("GetStats() = %+v", embedded)
+	}
+}
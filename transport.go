@@ -0,0 +1,153 @@
+package commonuseragent
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+)
+
+// DefaultStickyHostLimit is how many hosts a Transport with WithStickyHosts
+// remembers when no limit is configured.
+const DefaultStickyHostLimit = 4096
+
+// Transport is an http.RoundTripper that sets a User-Agent header chosen by
+// a Manager on every outgoing request. Requests that already carry a
+// User-Agent are sent unchanged.
+type Transport struct {
+	base        http.RoundTripper
+	manager     *Manager
+	choose      func(*Manager) string
+	sticky      bool
+	hostLimit   int
+	clientHints bool
+
+	// hosts indexes the sticky agents held in recent, which is ordered from
+	// most to least recently used so the oldest host can be evicted.
+	mu     sync.Mutex
+	hosts  map[string]*list.Element
+	recent *list.List
+}
+
+// stickyHost is one entry in Transport.recent.
+type stickyHost struct {
+	host string
+	ua   string
+}
+
+// TransportOption configures a Transport.
+type TransportOption func(*Transport)
+
+// WithTransportManager makes the Transport draw agents from m instead of the
+// package's default Manager.
+func WithTransportManager(m *Manager) TransportOption {
+	return func(t *Transport) {
+		t.manager = m
+	}
+}
+
+// WithUASelector replaces how the Transport picks a UA string, for example
+// (*Manager).GetRandomDesktopUA to send only desktop agents. The default is
+// (*Manager).GetRandomUA.
+func WithUASelector(choose func(*Manager) string) TransportOption {
+	return func(t *Transport) {
+		t.choose = choose
+	}
+}
+
+// WithStickyHosts keeps the first agent chosen for each host and reuses it
+// for later requests to that host, so a site sees one consistent client
+// while different sites see different ones. At most DefaultStickyHostLimit
+// hosts are remembered; when a new host arrives beyond that the least
+// recently used one is forgotten and gets a new agent on its next request.
+func WithStickyHosts() TransportOption {
+	return func(t *Transport) {
+		t.sticky = true
+	}
+}
+
+// WithStickyHostLimit changes how many hosts WithStickyHosts remembers.
+// Values below 1 keep DefaultStickyHostLimit.
+func WithStickyHostLimit(n int) TransportOption {
+	return func(t *Transport) {
+		t.hostLimit = n
+	}
+}
+
+// WithTransportClientHints also sets the Sec-CH-UA headers matching the
+// chosen agent, for browsers that send them.
+func WithTransportClientHints() TransportOption {
+	return func(t *Transport) {
+		t.clientHints = true
+	}
+}
+
+// NewTransport returns a Transport that sends requests through base, or
+// http.DefaultTransport if base is nil, with a rotating User-Agent. It makes
+// the package a drop-in for any http.Client:
+//
+//	client := &http.Client{Transport: commonuseragent.NewTransport(nil)}
+func NewTransport(base http.RoundTripper, opts ...TransportOption) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{
+		base:   base,
+		choose: (*Manager).GetRandomUA,
+		hosts:  make(map[string]*list.Element),
+		recent: list.New(),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.hostLimit < 1 {
+		t.hostLimit = DefaultStickyHostLimit
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper. The caller's request is not
+// modified; a clone carrying the headers is sent instead.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") != "" {
+		return t.base.RoundTrip(req)
+	}
+	ua := t.userAgent(req.URL.Host)
+	if ua == "" {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", ua)
+	if t.clientHints {
+		if hints, err := ClientHints(ua); err == nil {
+			hints.Apply(req.Header)
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// userAgent returns the UA to send to host.
+func (t *Transport) userAgent(host string) string {
+	m := t.manager
+	if m == nil {
+		m = Default()
+	}
+	if !t.sticky {
+		return t.choose(m)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e, ok := t.hosts[host]; ok {
+		t.recent.MoveToFront(e)
+		return e.Value.(stickyHost).ua
+	}
+	ua := t.choose(m)
+	t.hosts[host] = t.recent.PushFront(stickyHost{host, ua})
+	if t.recent.Len() > t.hostLimit {
+		oldest := t.recent.Back()
+		t.recent.Remove(oldest)
+		delete(t.hosts, oldest.Value.(stickyHost).host)
+	}
+	return ua
+}
@@ -0,0 +1,111 @@
+package commonuseragent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// recordingTransport returns a base transport that records the User-Agent
+// of every request it sees.
+func recordingTransport(seen *[]*http.Request) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		*seen = append(*seen, req)
+		return httptest.NewRecorder().Result(), nil
+	})
+}
+
+func TestTransportSetsUserAgent(t *testing.T) {
+	var seen []*http.Request
+	tr := NewTransport(recordingTransport(&seen),
+		WithUASelector((*Manager).GetRandomDesktopUA),
+		WithTransportClientHints())
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	if _, err := tr.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip returned an error: %v", err)
+	}
+	if req.Header.Get("User-Agent") != "" {
+		t.Errorf("RoundTrip modified the caller's request")
+	}
+
+	ua := seen[0].Header.Get("User-Agent")
+	found := false
+	for _, agent := range GetAllDesktop() {
+		found = found || agent.UA == ua
+	}
+	if !found {
+		t.Errorf("RoundTrip sent %q, which is not a desktop agent", ua)
+	}
+	if _, err := ClientHints(ua); err == nil && seen[0].Header.Get("Sec-CH-UA") == "" {
+		t.Errorf("RoundTrip did not set client hints for %q", ua)
+	}
+}
+
+func TestTransportKeepsExplicitUserAgent(t *testing.T) {
+	var seen []*http.Request
+	tr := NewTransport(recordingTransport(&seen))
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	req.Header.Set("User-Agent", "custom/1.0")
+	tr.RoundTrip(req)
+	if got := seen[0].Header.Get("User-Agent"); got != "custom/1.0" {
+		t.Errorf("RoundTrip replaced an explicit User-Agent with %q", got)
+	}
+}
+
+func TestTransportStickyHosts(t *testing.T) {
+	var seen []*http.Request
+	calls := 0
+	tr := NewTransport(recordingTransport(&seen),
+		WithStickyHosts(),
+		WithUASelector(func(*Manager) string {
+			calls++
+			return GetAllDesktop()[calls%len(GetAllDesktop())].UA
+		}))
+
+	for _, url := range []string{"https://a.example/", "https://a.example/x", "https://b.example/"} {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		tr.RoundTrip(req)
+	}
+	if seen[0].Header.Get("User-Agent") != seen[1].Header.Get("User-Agent") {
+		t.Errorf("sticky transport changed agents for the same host")
+	}
+	if seen[0].Header.Get("User-Agent") == seen[2].Header.Get("User-Agent") {
+		t.Errorf("sticky transport reused an agent across hosts")
+	}
+	if calls != 2 {
+		t.Errorf("selector called %d times, want 2", calls)
+	}
+}
+
+func TestTransportStickyHostLimit(t *testing.T) {
+	var seen []*http.Request
+	calls := 0
+	tr := NewTransport(recordingTransport(&seen),
+		WithStickyHosts(),
+		WithStickyHostLimit(2),
+		WithUASelector(func(*Manager) string {
+			calls++
+			return GetAllDesktop()[calls%len(GetAllDesktop())].UA
+		}))
+
+	// c evicts b, the least recently used host, so only b is chosen again.
+	for _, url := range []string{"https://a.example/", "https://b.example/", "https://a.example/", "https://c.example/", "https://a.example/", "https://b.example/"} {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		tr.RoundTrip(req)
+	}
+	if calls != 4 {
+		t.Errorf("selector called %d times, want 4", calls)
+	}
+	if len(tr.hosts) != 2 || tr.recent.Len() != 2 {
+		t.Errorf("transport remembers %d hosts, want 2", len(tr.hosts))
+	}
+}
@@ -1,65 +1,71 @@
 package commonuseragent
 
 import (
-	"embed"
-	"encoding/json"
-	"math/rand"
-	"time"
+	"sync"
 )
 
-// Go directive to embed the files in the binary.
-//
-//go:embed desktop_useragents.json
-//go:embed mobile_useragents.json
-var content embed.FS
+//go:generate go run gen_gzip.go
 
 type UserAgent struct {
 	UA  string  `json:"ua"`
 	Pct float64 `json:"pct"`
-}
 
-var desktopAgents []UserAgent
-var mobileAgents []UserAgent
+	// Metadata added in dataset schema version 2. It is derived from the UA
+	// string when a dataset does not provide it.
+	Browser        string      `json:"browser,omitempty"`
+	BrowserVersion string      `json:"browser_version,omitempty"`
+	OS             string      `json:"os,omitempty"`
+	Device         DeviceClass `json:"device,omitempty"`
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-	loadUserAgents("desktop_useragents.json", &desktopAgents)
-	loadUserAgents("mobile_useragents.json", &mobileAgents)
+	Engine Engine `json:"engine,omitempty"`
+	// Deprecated agents are kept in the dataset but never randomly selected.
+	Deprecated bool `json:"deprecated,omitempty"`
 }
 
-func loadUserAgents(filename string, agents *[]UserAgent) {
-	// Reading from the embedded file system
-	bytes, err := content.ReadFile(filename)
+// loadDefault builds the Manager behind the package-level functions on first
+// use, so programs that never call them don't pay for loading the datasets.
+var loadDefault = sync.OnceValues(func() (*Manager, error) {
+	m, err := NewManager()
 	if err != nil {
-		panic(err)
-	}
-	if err := json.Unmarshal(bytes, agents); err != nil {
-		panic(err)
+		// Keep the package usable: selections return zero values and the
+		// error is reported by GetInitError.
+		empty, _ := newManager(DefaultConfig(), nil, nil, nil)
+		return empty, err
 	}
+	return m, nil
+})
+
+// Default returns the Manager used by the package-level functions, loading
+// the embedded datasets on first use.
+func Default() *Manager {
+	m, _ := loadDefault()
+	return m
+}
+
+// GetInitError returns the error, if any, from loading the embedded datasets
+// into the default Manager. The package-level functions return zero values
+// when it is non-nil.
+func GetInitError() error {
+	_, err := loadDefault()
+	return err
 }
 
 func GetAllDesktop() []UserAgent {
-	return desktopAgents
+	return Default().GetAllDesktop()
 }
 
 func GetAllMobile() []UserAgent {
-	return mobileAgents
+	return Default().GetAllMobile()
 }
 
-// GetRandomDesktop returns a random UserAgent struct from the desktopAgents slice
+// GetRandomDesktop returns a random UserAgent struct from the desktop agents
 func GetRandomDesktop() UserAgent {
-	if len(desktopAgents) == 0 {
-		return UserAgent{}
-	}
-	return desktopAgents[rand.Intn(len(desktopAgents))]
+	return Default().GetRandomDesktop()
 }
 
-// GetRandomMobile returns a random UserAgent struct from the mobileAgents slice
+// GetRandomMobile returns a random UserAgent struct from the mobile agents
 func GetRandomMobile() UserAgent {
-	if len(mobileAgents) == 0 {
-		return UserAgent{}
-	}
-	return mobileAgents[rand.Intn(len(mobileAgents))]
+	return Default().GetRandomMobile()
 }
 
 // GetRandomDesktopUA returns just the UA string of a random desktop user agent
@@ -73,6 +79,5 @@ func GetRandomMobileUA() string {
 }
 
 func GetRandomUA() string {
-	allAgents := append(desktopAgents, mobileAgents...)
-	return allAgents[rand.Intn(len(allAgents))].UA
+	return Default().GetRandomUA()
 }
@@ -56,3 +56,12 @@ func TestGetRandomUserAgent(t *testing.T) {
 		t.Errorf("GetRandomUserAgent returned an empty user agent")
 	}
 }
+
+func TestGetInitError(t *testing.T) {
+	if err := GetInitError(); err != nil {
+		t.Fatalf("GetInitError() = %v, want nil for the embedded datasets", err)
+	}
+	if Default() != Default() {
+		t.Errorf("Default returned different Managers")
+	}
+}
@@ -0,0 +1,76 @@
+package commonuseragent
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+)
+
+// Default length bounds applied to user agent strings.
+const (
+	DefaultMinLength = 10
+	DefaultMaxLength = 1000
+)
+
+// ErrInvalidUserAgent is returned (wrapped) when a user agent fails validation.
+var ErrInvalidUserAgent = errors.New("invalid user agent")
+
+// ValidationRule checks a single user agent and returns an error describing
+// why it is unacceptable, or nil if it passes.
+type ValidationRule func(UserAgent) error
+
+// ValidationConfig controls which user agents a Manager accepts.
+type ValidationConfig struct {
+	// MinLength is the minimum UA length in bytes. Zero disables the check.
+	MinLength int
+	// MaxLength is the maximum UA length in bytes. Zero disables the check.
+	MaxLength int
+	// Rules are additional checks run after the length bounds.
+	Rules []ValidationRule
+}
+
+// DefaultValidationConfig returns the validation settings used when none are
+// configured: 10 to 1000 characters, no control characters and no negative Pct.
+func DefaultValidationConfig() ValidationConfig {
+	return ValidationConfig{
+		MinLength: DefaultMinLength,
+		MaxLength: DefaultMaxLength,
+		Rules:     []ValidationRule{NoControlChars, NonNegativePct},
+	}
+}
+
+// Validate checks ua against the configured limits and rules. The returned
+// error wraps ErrInvalidUserAgent.
+func (v ValidationConfig) Validate(ua UserAgent) error {
+	if v.MinLength > 0 && len(ua.UA) < v.MinLength {
+		return fmt.Errorf("%w: length %d is below minimum %d", ErrInvalidUserAgent, len(ua.UA), v.MinLength)
+	}
+	if v.MaxLength > 0 && len(ua.UA) > v.MaxLength {
+		return fmt.Errorf("%w: length %d exceeds maximum %d", ErrInvalidUserAgent, len(ua.UA), v.MaxLength)
+	}
+	for _, rule := range v.Rules {
+		if err := rule(ua); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidUserAgent, err)
+		}
+	}
+	return nil
+}
+
+// NoControlChars rejects user agents containing control characters, which
+// are never valid in an HTTP header value.
+func NoControlChars(ua UserAgent) error {
+	for _, r := range ua.UA {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("contains control character %q", r)
+		}
+	}
+	return nil
+}
+
+// NonNegativePct rejects user agents with a negative usage percentage.
+func NonNegativePct(ua UserAgent) error {
+	if ua.Pct < 0 {
+		return fmt.Errorf("negative pct %v", ua.Pct)
+	}
+	return nil
+}
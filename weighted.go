@@ -0,0 +1,129 @@
+package commonuseragent
+
+import (
+	"sort"
+)
+
+// cumulativeWeights holds the running total of Pct over a list of agents so
+// a weighted pick is a binary search instead of a linear scan. Deprecated
+// agents contribute no weight.
+type cumulativeWeights []float64
+
+func newCumulativeWeights(lists ...[]UserAgent) cumulativeWeights {
+	n := 0
+	for _, list := range lists {
+		n += len(list)
+	}
+	cum := make(cumulativeWeights, 0, n)
+	total := 0.0
+	for _, list := range lists {
+		for _, agent := range list {
+			if !agent.Deprecated && agent.Pct > 0 {
+				total += agent.Pct
+			}
+			cum = append(cum, total)
+		}
+	}
+	return cum
+}
+
+// total returns the sum of all weights.
+func (c cumulativeWeights) total() float64 {
+	if len(c) == 0 {
+		return 0
+	}
+	return c[len(c)-1]
+}
+
+// index returns a position chosen with probability proportional to its
+// weight, or -1 if there is no weight to choose by or rnd has run dry.
+func (c cumulativeWeights) index(rnd randSource) int {
+	total := c.total()
+	if total <= 0 {
+		return -1
+	}
+	f, ok := rnd.float64()
+	if !ok {
+		return -1
+	}
+	target := f * total
+	return sort.Search(len(c), func(i int) bool { return c[i] > target })
+}
+
+// pickByWeight returns an agent from lists chosen by weights, which must have
+// been built from the same lists. Like pick, it expects lists without
+// deprecated agents. It falls back to a uniform pick when no
+// agent has a positive Pct.
+func (s *snapshot) pickByWeight(rnd randSource, weights cumulativeWeights, lists ...[]UserAgent) UserAgent {
+	i := weights.index(rnd)
+	if i < 0 {
+		return s.pick(rnd, lists...)
+	}
+	for _, list := range lists {
+		if i < len(list) {
+			return list[i]
+		}
+		i -= len(list)
+	}
+	return UserAgent{}
+}
+
+// WithNormalizeWeights scales the Pct values of each dataset so they sum to
+// 100 after duplicates are merged, since hand-edited datasets rarely add up
+// exactly. Datasets without any usage share, such as tv, are left alone.
+func WithNormalizeWeights() Option {
+	return func(c *Config) {
+		c.NormalizeWeights = true
+	}
+}
+
+// normalizeWeights scales the Pct of agents in place to sum to 100.
+func normalizeWeights(agents []UserAgent) {
+	total := 0.0
+	for _, agent := range agents {
+		total += agent.Pct
+	}
+	if total <= 0 {
+		return
+	}
+	for i := range agents {
+		agents[i].Pct *= 100 / total
+	}
+}
+
+// GetWeightedRandomDesktop returns a desktop agent chosen with probability
+// proportional to its Pct, matching real-world market share.
+func (m *Manager) GetWeightedRandomDesktop() UserAgent {
+	s := m.snap.Load()
+	return m.served(m.start(), strategyWeighted, "desktop", s.pickByWeight(m.rnd, s.desktopWeights, s.activeDesktop))
+}
+
+// GetWeightedRandomMobile returns a mobile agent chosen with probability
+// proportional to its Pct, matching real-world market share.
+func (m *Manager) GetWeightedRandomMobile() UserAgent {
+	s := m.snap.Load()
+	return m.served(m.start(), strategyWeighted, "mobile", s.pickByWeight(m.rnd, s.mobileWeights, s.activeMobile))
+}
+
+// GetWeightedRandomUA returns the UA string of an agent from the combined
+// desktop and mobile lists chosen by Pct. Since each list's Pct sums to
+// roughly 100, desktop and mobile are about equally likely.
+func (m *Manager) GetWeightedRandomUA() string {
+	s := m.snap.Load()
+	return m.served(m.start(), strategyWeighted, "", s.pickByWeight(m.rnd, s.allWeights, s.activeDesktop, s.activeMobile)).UA
+}
+
+// GetWeightedRandomDesktop returns a desktop agent weighted by Pct.
+func GetWeightedRandomDesktop() UserAgent {
+	return Default().GetWeightedRandomDesktop()
+}
+
+// GetWeightedRandomMobile returns a mobile agent weighted by Pct.
+func GetWeightedRandomMobile() UserAgent {
+	return Default().GetWeightedRandomMobile()
+}
+
+// GetWeightedRandomUA returns a desktop or mobile UA string weighted by Pct.
+func GetWeightedRandomUA() string {
+	return Default().GetWeightedRandomUA()
+}
@@ -0,0 +1,91 @@
+package commonuseragent
+
+import (
+	"strings"
+	"testing"
+)
+
+// fixedSource returns the same value for every draw.
+type fixedSource struct {
+	f float64
+}
+
+func (s fixedSource) intn(n int) (int, bool)   { return int(s.f * float64(n)), true }
+func (s fixedSource) float64() (float64, bool) { return s.f, true }
+
+func TestCumulativeWeightsIndex(t *testing.T) {
+	agents := []UserAgent{{Pct: 10}, {Pct: 0}, {Pct: 30}, {Pct: 60, Deprecated: true}}
+	weights := newCumulativeWeights(agents)
+	if weights.total() != 40 {
+		t.Fatalf("total() = %v, want 40", weights.total())
+	}
+	tests := []struct {
+		f    float64
+		want int
+	}{
+		{0, 0},
+		{0.2, 0},
+		{0.25, 2},
+		{0.99, 2},
+	}
+	for _, tt := range tests {
+		if got := weights.index(fixedSource{tt.f}); got != tt.want {
+			t.Errorf("index(%v) = %d, want %d", tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestGetWeightedRandom(t *testing.T) {
+	if GetWeightedRandomDesktop().UA == "" {
+		t.Errorf("GetWeightedRandomDesktop returned an empty user agent")
+	}
+	if GetWeightedRandomMobile().UA == "" {
+		t.Errorf("GetWeightedRandomMobile returned an empty user agent")
+	}
+	if GetWeightedRandomUA() == "" {
+		t.Errorf("GetWeightedRandomUA returned an empty user agent")
+	}
+}
+
+func TestGetWeightedRandomDistribution(t *testing.T) {
+	m, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager returned an error: %v", err)
+	}
+	top := m.GetAllDesktop()[0]
+	const draws = 5000
+	hits := 0
+	for i := 0; i < draws; i++ {
+		if m.GetWeightedRandomDesktop().UA == top.UA {
+			hits++
+		}
+	}
+	// The most common desktop agent has a Pct of about 37; a uniform pick
+	// would return it less than 5% of the time.
+	if share := float64(hits) / draws * 100; share < 25 {
+		t.Errorf("most common agent was picked %.1f%% of the time, want about %.1f%%", share, top.Pct)
+	}
+}
+
+func TestWithNormalizeWeights(t *testing.T) {
+	desktop := strings.NewReader(`[
+		{"ua": "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", "pct": 30},
+		{"ua": "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0", "pct": 10},
+		{"ua": "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", "pct": 40}
+	]`)
+	mobile := strings.NewReader(`[{"ua": "Mozilla/5.0 (Android 14; Mobile; rv:125.0) Gecko/125.0 Firefox/125.0", "pct": 0}]`)
+	cfg := DefaultConfig()
+	WithNormalizeWeights()(&cfg)
+	m, err := NewManagerFromReaders(desktop, mobile, cfg)
+	if err != nil {
+		t.Fatalf("NewManagerFromReaders returned an error: %v", err)
+	}
+
+	agents := m.GetAllDesktop()
+	if len(agents) != 2 || agents[0].Pct != 50 || agents[1].Pct != 50 {
+		t.Errorf("normalized desktop = %+v, want two agents at 50", agents)
+	}
+	if pct := m.GetAllMobile()[0].Pct; pct != 0 {
+		t.Errorf("mobile dataset without weights was normalized to %v", pct)
+	}
+}